@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/config"
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/service"
+	"wb-l0-go/internal/transport/bus"
+	kafkaTransport "wb-l0-go/internal/transport/kafka"
+	natsTransport "wb-l0-go/internal/transport/nats"
+)
+
+// Реализации шины сообщений (MESSAGE_BUS)
+const (
+	busKafka  = "kafka"
+	busNATS   = "nats"
+	busMemory = "memory"
+)
+
+// messageBus — публикаторы и подписчики шины сообщений, выбранной в конфигурации.
+type messageBus struct {
+	// orders публикует заказы, events — события о сохранённых заказах из outbox
+	orders bus.Publisher
+	events bus.Publisher
+	// dlq принимает сообщения, которые consumer не смог обработать; nil, если DLQ не используется
+	dlq bus.Publisher
+	// subscribers[0] читает заказы, остальные — вспомогательные
+	subscribers []bus.Subscriber
+	// replayer и consumer равны nil для шин, отличных от Kafka
+	replayer *kafkaTransport.Replayer
+	consumer *kafkaTransport.Consumer
+}
+
+// newMessageBus создаёт шину сообщений. Подписчик на заказы передаёт их handler'у,
+// groupID — группа Kafka или durable consumer NATS.
+func newMessageBus(ctx context.Context, cfg config.Config, groupID string, handler bus.OrderHandler, svc *service.OrderService, log *zap.Logger) (*messageBus, error) {
+	switch cfg.MessageBus {
+	case busKafka:
+		return newKafkaBus(ctx, cfg, groupID, handler, svc, log)
+	case busNATS:
+		return newNATSBus(ctx, cfg, groupID, handler, log)
+	case busMemory:
+		orders := bus.NewMemory(cfg.MemoryBusSize)
+		events := bus.NewMemory(cfg.MemoryBusSize)
+		// Событиями в памяти никто, кроме лога, не пользуется, но их нужно разбирать, чтобы не переполнить буфер
+		logEvents := bus.HandlerFunc(func(_ context.Context, env domain.Envelope) error {
+			log.Debug("order event", zap.String("key", env.Key), zap.ByteString("payload", env.Payload))
+			return nil
+		})
+		return &messageBus{
+			orders:      orders,
+			events:      events,
+			subscribers: []bus.Subscriber{orders.Subscriber(handler, log), events.Subscriber(logEvents, log)},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown message bus %q", cfg.MessageBus)
+}
+
+func newKafkaBus(ctx context.Context, cfg config.Config, groupID string, handler bus.OrderHandler, svc *service.OrderService, log *zap.Logger) (*messageBus, error) {
+	sec, err := newKafkaSecurity(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kafka security settings: %w", err)
+	}
+	// В теневом режиме ничего не пишется, в том числе в DLQ
+	var dlq *kafkaTransport.Producer
+	if !cfg.ShadowMode {
+		dlq, err = kafkaTransport.NewProducer(cfg.KafkaBrokers, cfg.KafkaDLQTopic, kafkaTransport.ProducerOptions{
+			BatchSize:   1,
+			Compression: cfg.KafkaCompression,
+		}, sec, log)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dlq producer: %w", err)
+		}
+	}
+	closeDLQ := func() {
+		if dlq != nil {
+			_ = dlq.Close()
+		}
+	}
+	consumerOpts := kafkaTransport.ConsumerOptions{
+		StartOffset:       cfg.KafkaStartOffset,
+		MinBytes:          cfg.KafkaMinBytes,
+		MaxBytes:          cfg.KafkaMaxBytes,
+		MaxWait:           cfg.KafkaMaxWait,
+		CommitInterval:    cfg.KafkaCommitEvery,
+		Balancers:         cfg.KafkaBalancers,
+		SessionTimeout:    cfg.KafkaSession,
+		RebalanceTimeout:  cfg.KafkaRebalance,
+		HeartbeatInterval: cfg.KafkaHeartbeat,
+		WatchPartitions:   cfg.KafkaWatchParts,
+	}
+	if dlq != nil {
+		consumerOpts.DLQ = dlq
+	}
+	consumer, err := kafkaTransport.NewConsumer(ctx, cfg.KafkaBrokers, cfg.KafkaTopic, groupID, consumerOpts, sec, handler, log)
+	if err != nil {
+		closeDLQ()
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	orders, err := kafkaTransport.NewProducer(cfg.KafkaBrokers, cfg.KafkaTopic, kafkaTransport.ProducerOptions{
+		BatchSize:   cfg.PublishBatchSize,
+		Compression: cfg.KafkaCompression,
+	}, sec, log)
+	if err != nil {
+		_ = consumer.Close()
+		closeDLQ()
+		return nil, fmt.Errorf("failed to create producer: %w", err)
+	}
+	events, err := kafkaTransport.NewProducer(cfg.KafkaBrokers, cfg.KafkaEventsTopic, kafkaTransport.ProducerOptions{
+		BatchSize:   cfg.OutboxBatchSize,
+		Compression: cfg.KafkaCompression,
+	}, sec, log)
+	if err != nil {
+		_ = orders.Close()
+		_ = consumer.Close()
+		closeDLQ()
+		return nil, fmt.Errorf("failed to create events producer: %w", err)
+	}
+	b := &messageBus{
+		orders:      orders,
+		events:      events,
+		subscribers: []bus.Subscriber{consumer},
+		consumer:    consumer,
+		replayer:    kafkaTransport.NewReplayer(cfg.KafkaBrokers, cfg.KafkaTopic, cfg.KafkaDLQTopic, sec, svc, log),
+	}
+	if dlq != nil {
+		b.dlq = dlq
+	}
+	return b, nil
+}
+
+func newNATSBus(ctx context.Context, cfg config.Config, durable string, handler bus.OrderHandler, log *zap.Logger) (*messageBus, error) {
+	client, err := natsTransport.Connect(cfg.NATSURL, log)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.EnsureStream(ctx, cfg.NATSStream, cfg.NATSSubject); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	if err := client.EnsureStream(ctx, cfg.NATSEventsStream, cfg.NATSEventsSubj); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	// Подключение закрывается вместе с последним из публикаторов и подписчиков
+	return &messageBus{
+		orders:      client.Publisher(cfg.NATSSubject),
+		events:      client.Publisher(cfg.NATSEventsSubj),
+		subscribers: []bus.Subscriber{client.Subscriber(cfg.NATSStream, durable, handler)},
+	}, nil
+}
+
+// Close закрывает подписчиков, затем публикаторы. Подключения закрываются вместе с ними.
+func (b *messageBus) Close(log *zap.Logger) {
+	for _, s := range b.subscribers {
+		if err := s.Close(); err != nil {
+			log.Error("subscriber close error", zap.Error(err))
+		}
+	}
+	// DLQ закрывается после подписчиков: при остановке consumer ещё может отправлять в неё сообщения
+	if b.dlq != nil {
+		if err := b.dlq.Close(); err != nil {
+			log.Error("dlq producer close error", zap.Error(err))
+		}
+	}
+	if err := b.orders.Close(); err != nil {
+		log.Error("producer close error", zap.Error(err))
+	}
+	if err := b.events.Close(); err != nil {
+		log.Error("events producer close error", zap.Error(err))
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/cache"
+	"wb-l0-go/internal/codec"
+	"wb-l0-go/internal/config"
+	"wb-l0-go/internal/db"
+	"wb-l0-go/internal/feed"
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/repository"
+	"wb-l0-go/internal/service"
+	kafkaTransport "wb-l0-go/internal/transport/kafka"
+)
+
+// cliEnv — общие зависимости подкоманд командной строки.
+type cliEnv struct {
+	cfg  config.Config
+	log  *zap.Logger
+	pool *pgxpool.Pool
+	svc  *service.OrderService
+}
+
+// newCLIEnv загружает конфигурацию, подключается к БД и создаёт сервис заказов.
+// Кэш и лента заказов подкомандам не нужны, поэтому создаются минимального размера.
+func newCLIEnv(ctx context.Context) (*cliEnv, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	log, err := logger.New(cfg.LogLevel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init logger: %w", err)
+	}
+	pool, err := db.NewPool(ctx, cfg.PostgresDSN)
+	if err != nil {
+		_ = log.Sync()
+		return nil, fmt.Errorf("failed to connect db: %w", err)
+	}
+	codecs, err := newCodecs(cfg)
+	if err != nil {
+		pool.Close()
+		_ = log.Sync()
+		return nil, err
+	}
+	repo := repository.NewPostgresOrderRepository(pool)
+	outbox := repository.NewPostgresOutboxRepository(pool)
+	svc := service.NewOrderService(repo, outbox, cache.NewMemoryCache(1), codecs, log, pool, feed.New(1))
+	return &cliEnv{cfg: cfg, log: log, pool: pool, svc: svc}, nil
+}
+
+func (e *cliEnv) Close() {
+	e.pool.Close()
+	_ = e.log.Sync()
+}
+
+// newCodecs создаёт кодеки сообщений шины. Схемы Avro из AVRO_SCHEMA_DIR добавляются
+// к встроенной схеме заказа.
+func newCodecs(cfg config.Config) (*codec.Codecs, error) {
+	schemas := codec.NewLocalSchemaRegistry()
+	if cfg.AvroSchemaDir != "" {
+		if err := schemas.LoadDir(cfg.AvroSchemaDir); err != nil {
+			return nil, fmt.Errorf("failed to load avro schemas: %w", err)
+		}
+	}
+	return codec.New(schemas), nil
+}
+
+// newKafkaSecurity настраивает TLS и SASL подключения к Kafka из конфигурации.
+func newKafkaSecurity(cfg config.Config) (*kafkaTransport.Security, error) {
+	return kafkaTransport.NewSecurity(kafkaTransport.SecurityConfig{
+		TLS:                cfg.KafkaTLS,
+		CAFile:             cfg.KafkaTLSCAFile,
+		CertFile:           cfg.KafkaTLSCert,
+		KeyFile:            cfg.KafkaTLSKey,
+		ServerName:         cfg.KafkaTLSServer,
+		InsecureSkipVerify: cfg.KafkaTLSInsecure,
+		SASLMechanism:      cfg.KafkaSASLMech,
+		SASLUsername:       cfg.KafkaSASLUser,
+		SASLPassword:       cfg.KafkaSASLPass,
+	})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/export"
+	"wb-l0-go/internal/importer"
+	"wb-l0-go/internal/repository"
+	"wb-l0-go/internal/service"
+)
+
+// runImport выполняет подкоманду import: загружает заказы из NDJSON или CSV файла,
+// пишет отклонённые записи в отчёт и выводит итог в stdout. Возвращает код выхода.
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "формат файла: ndjson или csv (по умолчанию по расширению)")
+	importID := fs.String("id", "", "идентификатор импорта для продолжения (по умолчанию имя файла)")
+	reportPath := fs.String("report", "", "файл отчёта об отклонённых записях (по умолчанию FILE.rejected.ndjson)")
+	restart := fs.Bool("restart", false, "начать импорт с начала, сбросив сохранённую позицию")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: app import [flags] FILE")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+
+	if *format == "" {
+		*format = string(export.FormatNDJSON)
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			*format = string(export.FormatCSV)
+		}
+	}
+	if *importID == "" {
+		*importID = filepath.Base(path)
+	}
+	if *reportPath == "" {
+		*reportPath = path + ".rejected.ndjson"
+	}
+
+	// Прерывание по сигналу оставляет сохранённую позицию, импорт можно продолжить повторным запуском
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	env, err := newCLIEnv(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer env.Close()
+	log := env.log
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Error("failed to open import file", zap.Error(err))
+		return 1
+	}
+	defer f.Close()
+
+	fileFormat, err := export.ParseFormat(*format)
+	if err != nil {
+		log.Error("invalid format", zap.Error(err))
+		return 2
+	}
+	reader, err := importer.NewReader(fileFormat, f)
+	if err != nil {
+		log.Error("failed to read import file", zap.Error(err))
+		return 1
+	}
+
+	report, reportedLine, err := openReport(*reportPath, *restart)
+	if err != nil {
+		log.Error("failed to open report file", zap.Error(err))
+		return 1
+	}
+	defer report.Close()
+	reportEnc := json.NewEncoder(report)
+
+	importSvc := service.NewImportService(env.svc, repository.NewPostgresImportCheckpointRepository(env.pool), env.cfg.ImportBatchSize)
+
+	res, err := importSvc.Import(ctx, reader, service.ImportOptions{
+		ImportID:     *importID,
+		Restart:      *restart,
+		ReportedLine: reportedLine,
+		OnReject: func(rej service.ImportRejection) {
+			if err := reportEnc.Encode(rej); err != nil {
+				log.Error("failed to write report", zap.Error(err))
+			}
+		},
+	})
+
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	_ = out.Encode(res)
+	if err != nil {
+		log.Error("import interrupted, run the same command again to resume", zap.Int("last_line", res.LastLine), zap.Error(err))
+		return 1
+	}
+	return 0
+}
+
+// openReport открывает отчёт об отклонённых записях для записи. При перезапуске отчёт
+// начинается заново. При продолжении импорта отчёт дописывается, и возвращается последняя
+// уже записанная в него строка файла импорта: прерванный запуск мог отклонить строки после
+// сохранённой позиции, и продолжение не должно записать их повторно. Неполная последняя
+// запись, оставшаяся после прерывания во время записи, отбрасывается.
+func openReport(path string, restart bool) (*os.File, int, error) {
+	if restart {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		return f, 0, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, 0, err
+	}
+	var lastLine int
+	var valid int64
+	r := bufio.NewReader(f)
+	for {
+		raw, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+		var rej service.ImportRejection
+		if json.Unmarshal(raw, &rej) != nil {
+			break
+		}
+		lastLine = max(lastLine, rej.Line)
+		valid += int64(len(raw))
+	}
+	if err := f.Truncate(valid); err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	if _, err := f.Seek(valid, io.SeekStart); err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	return f, lastLine, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.rejected.ndjson")
+	// Запись строки 5 оборвана прерыванием
+	require.NoError(t, os.WriteFile(path, []byte(`{"line":2,"reason":"a"}`+"\n"+`{"line":4,"reason":"b"}`+"\n"+`{"line":5,"rea`), 0o644))
+
+	f, line, err := openReport(path, false)
+	require.NoError(t, err)
+	assert.Equal(t, 4, line)
+	_, err = f.WriteString(`{"line":5,"reason":"c"}` + "\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"line":2,"reason":"a"}`+"\n"+`{"line":4,"reason":"b"}`+"\n"+`{"line":5,"reason":"c"}`+"\n", string(data))
+
+	// Перезапуск начинает отчёт заново
+	f, line, err = openReport(path, true)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Zero(t, line)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	// Отчёт создаётся, если его ещё нет
+	f, line, err = openReport(filepath.Join(t.TempDir(), "new.ndjson"), false)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Zero(t, line)
+}
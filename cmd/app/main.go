@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -15,19 +16,32 @@ import (
 	"wb-l0-go/internal/cache"
 	"wb-l0-go/internal/config"
 	"wb-l0-go/internal/db"
+	"wb-l0-go/internal/feed"
 	"wb-l0-go/internal/logger"
 	"wb-l0-go/internal/repository"
 	"wb-l0-go/internal/service"
+	"wb-l0-go/internal/transport/bus"
+	graphqlTransport "wb-l0-go/internal/transport/graphql"
+	grpcTransport "wb-l0-go/internal/transport/grpc"
 	httpHandler "wb-l0-go/internal/transport/http"
-	kafkaTransport "wb-l0-go/internal/transport/kafka"
 )
 
 // @title           WB L0 Go API
 // @version         1.0
 // @description     API for WB L0 Go project
 // @host            localhost:8080
-// @BasePath        /
+// @BasePath        /api/v1
 func main() {
+	// Подкоманды: app import [flags] FILE, app replay [flags]
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			os.Exit(runImport(os.Args[2:]))
+		case "replay":
+			os.Exit(runReplay(os.Args[2:]))
+		}
+	}
+
 	// Загружаем конфигурацию
 	cfg, err := config.Load()
 	if err != nil {
@@ -50,14 +64,41 @@ func main() {
 	defer pool.Close()
 
 	// Инициализируем репозиторий и сервис
+	codecs, err := newCodecs(cfg)
+	if err != nil {
+		log.Panic("failed to init codecs", zap.Error(err))
+	}
 	repo := repository.NewPostgresOrderRepository(pool)
+	outboxRepo := repository.NewPostgresOutboxRepository(pool)
 	memCache := cache.NewMemoryCache(cfg.CacheMaxItems)
-	svc := service.NewOrderService(repo, memCache, log, pool)
+	orderFeed := feed.New(cfg.FeedHistorySize)
+	svc := service.NewOrderService(repo, outboxRepo, memCache, codecs, log, pool, orderFeed)
 	memCache.Load(ctx, svc)
+	importSvc := service.NewImportService(svc, repository.NewPostgresImportCheckpointRepository(pool), cfg.ImportBatchSize)
+
+	// В теневом режиме consumer читает заказы отдельной группой и ничего не сохраняет
+	var orderHandler bus.OrderHandler = svc
+	groupID := cfg.KafkaGroupID
+	var shadow *service.ShadowService
+	if cfg.ShadowMode {
+		shadow = service.NewShadowService(svc, log)
+		orderHandler, groupID = shadow, cfg.ShadowGroupID
+		log.Warn("consumer runs in shadow mode, orders will not be stored", zap.String("group_id", groupID))
+	}
 
-	// Инициализируем Kafka producer и consumer
-	producer := kafkaTransport.NewProducer(cfg.KafkaBrokers, cfg.KafkaTopic, log)
-	consumer := kafkaTransport.NewConsumer(cfg.KafkaBrokers, cfg.KafkaTopic, cfg.KafkaGroupID, svc, log)
+	// Инициализируем шину сообщений: Kafka, NATS JetStream или шину в памяти процесса
+	msgBus, err := newMessageBus(ctx, cfg, groupID, orderHandler, svc, log)
+	if err != nil {
+		log.Panic("failed to init message bus", zap.Error(err), zap.String("bus", cfg.MessageBus))
+	}
+	// Relay публикует события из outbox в топик событий заказов
+	relay := bus.NewOutboxRelay(outboxRepo, msgBus.events, bus.OutboxRelayOptions{
+		PollInterval:    cfg.OutboxPoll,
+		BatchSize:       cfg.OutboxBatchSize,
+		Retention:       cfg.OutboxRetention,
+		RetryBackoff:    cfg.OutboxBackoff,
+		MaxRetryBackoff: cfg.OutboxMaxBackoff,
+	}, log)
 
 	// Контекст graceful shutdown по сигналам
 	shutdownCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -76,27 +117,62 @@ func main() {
 		os.Exit(1)
 	}()
 
-	// Запускаем consumer в отдельной горутине
+	// Запускаем подписчиков шины в отдельных горутинах
 	var wg sync.WaitGroup
+	for _, sub := range msgBus.subscribers {
+		wg.Go(func() {
+			if err := sub.Run(shutdownCtx); err != nil && err != context.Canceled {
+				log.Error("consumer stopped with error", zap.Error(err))
+				// Инициируем общий shutdown, чтобы корректно закрыть остальные компоненты
+				stop()
+			} else {
+				log.Info("consumer stopped")
+			}
+		})
+	}
+
 	wg.Go(func() {
-		if err := consumer.Run(shutdownCtx); err != nil && err != context.Canceled {
-			log.Error("consumer stopped with error", zap.Error(err))
-			// Инициируем общий shutdown, чтобы корректно закрыть остальные компоненты
-			stop()
+		if err := relay.Run(shutdownCtx); err != nil && err != context.Canceled {
+			log.Error("outbox relay stopped with error", zap.Error(err))
 		} else {
-			log.Info("consumer stopped")
+			log.Info("outbox relay stopped")
 		}
 	})
 
+	if shadow != nil {
+		wg.Go(func() {
+			shadow.ReportLoop(shutdownCtx, cfg.ShadowReport)
+		})
+	}
+
 	// Инициализируем HTTP сервер
-	r := gin.Default()
-	h := httpHandler.NewHandler(svc, producer, log)
-	h.RegisterRoutes(r)
+	r := gin.New()
+	r.Use(httpHandler.RequestID(), httpHandler.AccessLog(log), httpHandler.Recovery(log))
+	r.NoRoute(httpHandler.NoRoute)
+	h := httpHandler.NewHandler(svc, importSvc, msgBus.orders, cfg.FeedHeartbeat, cfg.OrderMaxAge, log)
+	h.RegisterUI(r)
+	admin := httpHandler.NewAdminHandler(msgBus.replayer, msgBus.consumer, shadow, log)
+	httpHandler.RegisterAPI(r, cfg.LegacyAPISunset, h, admin)
+	health := httpHandler.NewHealthHandler(func() bool {
+		return msgBus.consumer != nil && msgBus.consumer.Draining()
+	})
+	health.RegisterRoutes(r)
+	gql, err := graphqlTransport.NewHandler(svc, graphqlTransport.Limits{
+		MaxDepth:      cfg.GraphQLMaxDepth,
+		MaxComplexity: cfg.GraphQLMaxCost,
+	}, log)
+	if err != nil {
+		log.Panic("failed to build graphql schema", zap.Error(err))
+	}
+	gql.RegisterRoutes(r)
 
 	srv := &http.Server{
 		Addr:    cfg.HTTPAddr,
 		Handler: r,
 	}
+	// Shutdown не ждёт hijacked WebSocket соединений и не прерывает открытые SSE потоки —
+	// завершаем их сами, как только сервер перестаёт принимать запросы
+	srv.RegisterOnShutdown(h.CloseStreams)
 
 	// Запуск HTTP сервера
 	go func() {
@@ -107,27 +183,41 @@ func main() {
 		}
 	}()
 
+	// Запуск gRPC сервера рядом с HTTP (пустой GRPC_ADDR отключает gRPC)
+	var grpcSrv *grpcTransport.Server
+	if cfg.GRPCAddr != "" {
+		lis, err := net.Listen("tcp", cfg.GRPCAddr)
+		if err != nil {
+			log.Panic("failed to listen grpc", zap.Error(err), zap.String("addr", cfg.GRPCAddr))
+		}
+		grpcSrv = grpcTransport.NewServer(svc, msgBus.orders, log)
+		go func() {
+			if err := grpcSrv.Serve(lis); err != nil {
+				log.Error("grpc server error", zap.Error(err))
+				stop()
+			}
+		}()
+	}
+
 	// Ожидаем сигнал завершения
 	<-shutdownCtx.Done()
 	log.Info("shutting down")
 
-	// Корректно останавливаем HTTP сервер (перестаёт принимать новые запросы и ждёт текущие)
+	// Корректно останавливаем HTTP и gRPC серверы (перестают принимать новые запросы и ждут текущие)
 	httpShutdownCtx, httpCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer httpCancel()
 	if err := srv.Shutdown(httpShutdownCtx); err != nil {
 		log.Error("http shutdown error", zap.Error(err))
 	}
+	if grpcSrv != nil {
+		if err := grpcSrv.Shutdown(httpShutdownCtx); err != nil {
+			log.Error("grpc shutdown error", zap.Error(err))
+		}
+	}
 
-	// Дожидаемся завершения consumer
+	// Дожидаемся завершения consumer и outbox relay
 	wg.Wait()
 
-	// Закрываем consumer
-	if err := consumer.Close(); err != nil {
-		log.Error("consumer close error", zap.Error(err))
-	}
-
-	// Закрываем producer (после остановки HTTP, чтобы не ломать обработчики)
-	if err := producer.Close(); err != nil {
-		log.Error("producer close error", zap.Error(err))
-	}
+	// Закрываем подписчиков и producer'ы (после остановки HTTP, чтобы не ломать обработчики)
+	msgBus.Close(log)
 }
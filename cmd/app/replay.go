@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	kafkaTransport "wb-l0-go/internal/transport/kafka"
+)
+
+// runReplay выполняет подкоманду replay: повторно обрабатывает сообщения топика заказов
+// (или DLQ) из заданного диапазона и выводит отчёт в stdout. Возвращает код выхода.
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	dlq := fs.Bool("dlq", false, "читать DLQ топик вместо топика заказов")
+	partition := fs.Int("partition", -1, "номер партиции (по умолчанию все партиции)")
+	fromOffset := fs.Int64("from-offset", -1, "первый обрабатываемый offset")
+	fromTime := fs.String("from-time", "", "обрабатывать сообщения не раньше этого времени (RFC3339)")
+	toOffset := fs.Int64("to-offset", -1, "последний обрабатываемый offset (включительно)")
+	toTime := fs.String("to-time", "", "обрабатывать сообщения раньше этого времени (RFC3339)")
+	apply := fs.Bool("apply", false, "сохранять заказы (по умолчанию только разбор и валидация)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: app replay (-from-offset N | -from-time T) [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	opts := kafkaTransport.ReplayOptions{DLQ: *dlq, Apply: *apply}
+	if *partition >= 0 {
+		opts.Partition = partition
+	}
+	if *fromOffset >= 0 {
+		opts.FromOffset = fromOffset
+	}
+	if *toOffset >= 0 {
+		opts.ToOffset = toOffset
+	}
+	for _, t := range []struct {
+		raw string
+		dst **time.Time
+	}{{*fromTime, &opts.FromTime}, {*toTime, &opts.ToTime}} {
+		if t.raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, t.raw)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid time:", err)
+			return 2
+		}
+		*t.dst = &parsed
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	env, err := newCLIEnv(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer env.Close()
+
+	if env.cfg.MessageBus != busKafka {
+		fmt.Fprintf(os.Stderr, "replay is only available with kafka message bus (MESSAGE_BUS=%s)\n", env.cfg.MessageBus)
+		return 2
+	}
+	sec, err := newKafkaSecurity(env.cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid kafka security settings:", err)
+		return 1
+	}
+	replayer := kafkaTransport.NewReplayer(env.cfg.KafkaBrokers, env.cfg.KafkaTopic, env.cfg.KafkaDLQTopic, sec, env.svc, env.log)
+	report, err := replayer.Replay(ctx, opts)
+
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	_ = out.Encode(report)
+	if err != nil {
+		env.log.Error("replay failed", zap.Error(err))
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,10 @@
+package main
+
+// Общая информация документации неверсионируемых endpoint'ов: GraphQL и пробы не входят
+// в REST API /api/v1 и описываются отдельным экземпляром Swagger (docs/root).
+
+// @title           WB L0 Go API (unversioned)
+// @version         1.0
+// @description     GraphQL and health endpoints of WB L0 Go project, not versioned with the REST API
+// @host            localhost:8080
+// @BasePath        /
@@ -1,323 +0,0 @@
-// Package docs Code generated by swaggo/swag. DO NOT EDIT
-package docs
-
-import "github.com/swaggo/swag"
-
-const docTemplate = `{
-    "schemes": {{ marshal .Schemes }},
-    "swagger": "2.0",
-    "info": {
-        "description": "{{escape .Description}}",
-        "title": "{{.Title}}",
-        "contact": {},
-        "version": "{{.Version}}"
-    },
-    "host": "{{.Host}}",
-    "basePath": "{{.BasePath}}",
-    "paths": {
-        "/orders": {
-            "get": {
-                "description": "Получить список uid заказов",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "orders"
-                ],
-                "summary": "Список uid заказов",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/orders/{order_uid}": {
-            "get": {
-                "description": "Получить заказ по uid",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "orders"
-                ],
-                "summary": "Получить заказ по uid",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Order UID",
-                        "name": "order_uid",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/domain.Order"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        },
-        "/publish": {
-            "post": {
-                "description": "Опубликовать заказ в Kafka",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "orders"
-                ],
-                "summary": "Опубликовать заказ",
-                "parameters": [
-                    {
-                        "description": "Order",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.Order"
-                        }
-                    }
-                ],
-                "responses": {
-                    "202": {
-                        "description": "Accepted",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "domain.Delivery": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "city": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "phone": {
-                    "type": "string"
-                },
-                "region": {
-                    "type": "string"
-                },
-                "zip": {
-                    "type": "string"
-                }
-            }
-        },
-        "domain.Items": {
-            "type": "object",
-            "properties": {
-                "brand": {
-                    "type": "string"
-                },
-                "chrt_id": {
-                    "type": "integer"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "nm_id": {
-                    "type": "integer"
-                },
-                "price": {
-                    "type": "integer"
-                },
-                "rid": {
-                    "type": "string"
-                },
-                "sale": {
-                    "type": "integer"
-                },
-                "size": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "integer"
-                },
-                "total_price": {
-                    "type": "integer"
-                },
-                "track_number": {
-                    "type": "string"
-                }
-            }
-        },
-        "domain.Order": {
-            "type": "object",
-            "properties": {
-                "customer_id": {
-                    "type": "string"
-                },
-                "date_created": {
-                    "type": "string"
-                },
-                "delivery": {
-                    "$ref": "#/definitions/domain.Delivery"
-                },
-                "delivery_service": {
-                    "type": "string"
-                },
-                "entry": {
-                    "type": "string"
-                },
-                "internal_signature": {
-                    "type": "string"
-                },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/domain.Items"
-                    }
-                },
-                "locale": {
-                    "type": "string"
-                },
-                "oof_shard": {
-                    "type": "string"
-                },
-                "order_uid": {
-                    "type": "string"
-                },
-                "payment": {
-                    "$ref": "#/definitions/domain.Payment"
-                },
-                "shardkey": {
-                    "type": "string"
-                },
-                "sm_id": {
-                    "type": "integer"
-                },
-                "track_number": {
-                    "type": "string"
-                }
-            }
-        },
-        "domain.Payment": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "integer"
-                },
-                "bank": {
-                    "type": "string"
-                },
-                "currency": {
-                    "type": "string"
-                },
-                "custom_fee": {
-                    "type": "integer"
-                },
-                "delivery_cost": {
-                    "type": "integer"
-                },
-                "goods_total": {
-                    "type": "integer"
-                },
-                "payment_dt": {
-                    "type": "integer"
-                },
-                "provider": {
-                    "type": "string"
-                },
-                "request_id": {
-                    "type": "string"
-                },
-                "transaction": {
-                    "type": "string"
-                }
-            }
-        }
-    }
-}`
-
-// SwaggerInfo holds exported Swagger Info so clients can modify it
-var SwaggerInfo = &swag.Spec{
-	Version:          "1.0",
-	Host:             "localhost:8080",
-	BasePath:         "/",
-	Schemes:          []string{},
-	Title:            "WB L0 Go API",
-	Description:      "API for WB L0 Go project",
-	InfoInstanceName: "swagger",
-	SwaggerTemplate:  docTemplate,
-	LeftDelim:        "{{",
-	RightDelim:       "}}",
-}
-
-func init() {
-	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
-}
@@ -0,0 +1,241 @@
+// Package root Code generated by swaggo/swag. DO NOT EDIT
+package root
+
+import "github.com/swaggo/swag"
+
+const docTemplateroot = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/graphql": {
+            "get": {
+                "description": "Выборочное получение полей заказов. Запросы order(orderUid) и orders(customerId, deliveryService, from, to, limit, offset).\nОшибки разбора, валидации и превышения лимитов глубины и сложности возвращаются со статусом 200 в поле errors.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "GraphQL запрос",
+                "parameters": [
+                    {
+                        "description": "Запрос (для POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Request"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Запрос (для GET)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Переменные в JSON (для GET)",
+                        "name": "variables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя операции (для GET)",
+                        "name": "operationName",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Выборочное получение полей заказов. Запросы order(orderUid) и orders(customerId, deliveryService, from, to, limit, offset).\nОшибки разбора, валидации и превышения лимитов глубины и сложности возвращаются со статусом 200 в поле errors.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "graphql"
+                ],
+                "summary": "GraphQL запрос",
+                "parameters": [
+                    {
+                        "description": "Запрос (для POST)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Request"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Запрос (для GET)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Переменные в JSON (для GET)",
+                        "name": "variables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя операции (для GET)",
+                        "name": "operationName",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Возвращает 503, после того как consumer переведён в режим drain.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "graphql.Request": {
+            "type": "object",
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {}
+                }
+            }
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "instance": {
+                    "description": "Instance — путь запроса, при обработке которого произошла ошибка",
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "RequestID — идентификатор запроса из заголовка X-Request-ID",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type — URI типа ошибки, about:blank означает, что смысл ошибки определяется кодом ответа",
+                    "type": "string"
+                }
+            }
+        }
+    }
+}`
+
+// SwaggerInforoot holds exported Swagger Info so clients can modify it
+var SwaggerInforoot = &swag.Spec{
+	Version:          "1.0",
+	Host:             "localhost:8080",
+	BasePath:         "/",
+	Schemes:          []string{},
+	Title:            "WB L0 Go API (unversioned)",
+	Description:      "GraphQL and health endpoints of WB L0 Go project, not versioned with the REST API",
+	InfoInstanceName: "root",
+	SwaggerTemplate:  docTemplateroot,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInforoot.InstanceName(), SwaggerInforoot)
+}
@@ -0,0 +1,1882 @@
+// Package v1 Code generated by swaggo/swag. DO NOT EDIT
+package v1
+
+import "github.com/swaggo/swag"
+
+const docTemplatev1 = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/admin/consumer": {
+            "get": {
+                "description": "Назначенные партиции, обработанные и закоммиченные offset'ы, high watermark'и, лаг, время последнего сообщения,\nколичество ошибок и признак паузы. Если брокеры недоступны, возвращаются внутренние счётчики и broker_error.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Состояние consumer'а Kafka",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/kafka.ConsumerStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/consumer/drain": {
+            "post": {
+                "description": "Останавливает чтение, дожидается обработки и коммита текущего сообщения и выводит consumer из группы.\nПосле этого /readyz отвечает 503. Возобновить чтение можно только перезапуском приложения.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Drain consumer'а",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/consumer/pause": {
+            "post": {
+                "description": "Consumer дообрабатывает текущее сообщение и перестаёт читать новые, оставаясь в группе.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Приостановить чтение заказов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/consumer/resume": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Возобновить чтение заказов",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/replay": {
+            "post": {
+                "description": "Читает топик заказов (или DLQ) с заданного offset'а или времени отдельным reader'ом без группы\nи прогоняет сообщения через HandleOrder (apply) или только через разбор и валидацию (dry-run).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Повторная обработка сообщений Kafka",
+                "parameters": [
+                    {
+                        "description": "Параметры повторной обработки",
+                        "name": "options",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/kafka.ReplayOptions"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/kafka.ReplayReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Отчёт до ошибки — в поле report",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/shadow": {
+            "get": {
+                "description": "Что сделал бы consumer с сообщениями, обработанными в теневом режиме (SHADOW_MODE=true):\nсколько заказов было бы сохранено впервые, перезаписано с изменениями, осталось бы без изменений или отклонено.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Сводка теневого режима",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/service.ShadowSummary"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/{customer_id}/orders": {
+            "get": {
+                "description": "Заказы покупателя целиком, начиная с последних сохранённых.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Заказы покупателя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID покупателя",
+                        "name": "customer_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Order"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/customers/{customer_id}/summary": {
+            "get": {
+                "description": "Количество заказов, сумма оплат по валютам, даты первого и последнего заказа\nи бренды с наибольшим количеством купленных товаров.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Сводка по покупателю",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID покупателя",
+                        "name": "customer_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.CustomerSummary"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/items": {
+            "get": {
+                "description": "Товары, совпадающие по всем заданным параметрам, вместе с заказом, в котором они находятся.\nДолжен быть задан хотя бы один из rid, nm_id и chrt_id.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Поиск товаров",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RID товара",
+                        "name": "rid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "nm_id товара",
+                        "name": "nm_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "chrt_id товара",
+                        "name": "chrt_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.ItemMatch"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders": {
+            "get": {
+                "description": "Получить список uid заказов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Список uid заказов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по customer_id",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по delivery_service",
+                        "name": "delivery_service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_created не раньше (RFC3339 или YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_created раньше (RFC3339 или YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/export": {
+            "get": {
+                "description": "Потоковая выгрузка заказов в CSV, NDJSON или XLSX. В CSV и XLSX каждая строка соответствует\nодному товару заказа. Фильтры совпадают с фильтрами списка заказов.",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Выгрузка заказов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Формат выгрузки: csv, ndjson или xlsx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по customer_id",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по delivery_service",
+                        "name": "delivery_service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_created не раньше (RFC3339 или YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_created раньше (RFC3339 или YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/import": {
+            "post": {
+                "description": "Загружает заказы из NDJSON или CSV (в формате выгрузки /orders/export). Файл передаётся телом запроса\nили полем file multipart-формы. Невалидные записи отклоняются с указанием строки и причины.\nПовторный запрос с тем же import_id продолжает прерванный импорт.",
+                "consumes": [
+                    "application/x-ndjson",
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Импорт заказов из файла",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Формат файла: ndjson или csv (по умолчанию определяется по Content-Type)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор импорта для продолжения после прерывания",
+                        "name": "import_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Начать импорт с начала, сбросив сохранённую позицию",
+                        "name": "restart",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл импорта",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.importResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Импорт с тем же import_id уже выполняется",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Результат до ошибки — в поле result",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/search": {
+            "get": {
+                "description": "Поиск по имени получателя, городу, адресу, названиям и брендам товаров. Запрос поддерживает\nсинтаксис websearch_to_tsquery: фразы в кавычках, \"-\" для исключения слова, \"or\" для альтернатив.\nРезультаты упорядочены по релевантности, highlight содержит фрагменты с найденными словами\nв \u003cmark\u003e\u003c/mark\u003e (остальной текст экранирован как HTML).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Полнотекстовый поиск заказов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SearchPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/stream": {
+            "get": {
+                "description": "Server-Sent Events поток заказов, сохранённых consumer'ом. Каждое событие содержит заказ в data\nи его ID в поле id. Для продолжения после обрыва передайте Last-Event-ID или last_event_id.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Лента новых заказов (SSE)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по customer_id",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по delivery_service",
+                        "name": "delivery_service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID последнего полученного события",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID последнего полученного события",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/ws": {
+            "get": {
+                "description": "WebSocket поток заказов, сохранённых consumer'ом. Каждое сообщение — JSON объект с полями id, time и order.\nДля продолжения после обрыва передайте last_event_id.",
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Лента новых заказов (WebSocket)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Фильтр по customer_id",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по delivery_service",
+                        "name": "delivery_service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID последнего полученного события",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/feed.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{order_uid}": {
+            "get": {
+                "description": "Получить заказ по uid. Ответ содержит ETag (хэш сохранённого заказа), Last-Modified (время\nпоследнего сохранения) и Cache-Control. Если заказ не изменился, условный запрос\nс If-None-Match или If-Modified-Since получает 304 без тела.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Получить заказ по uid",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order UID",
+                        "name": "order_uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag ранее полученного заказа",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified ранее полученного заказа",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Order"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Политика кэширования"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Сильный ETag заказа"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Время последнего сохранения заказа"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Заказ не изменился"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/publish": {
+            "post": {
+                "description": "Опубликовать заказ в Kafka. С параметром wait (например, 5s) обработчик дожидается,\nпока consumer сохранит заказ, и возвращает сохранённый заказ или ошибку валидации.\nX-Correlation-ID и X-Source-System передаются в заголовках сообщения и сохраняются вместе\nс заказом. Если X-Correlation-ID не задан, он генерируется и возвращается в ответе.\nТело публикуется без изменений, Content-Type запроса задаёт content-type сообщения: JSON (по умолчанию),\nprotobuf, Avro или MessagePack. Для неподдерживаемого формата возвращается 415.",
+                "consumes": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/avro",
+                    "application/msgpack"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Опубликовать заказ",
+                "parameters": [
+                    {
+                        "description": "Order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.Order"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Максимальное время ожидания сохранения заказа (например, 5s, не больше 30s)",
+                        "name": "wait",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID для сквозной трассировки заказа",
+                        "name": "X-Correlation-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Система, публикующая заказ",
+                        "name": "X-Source-System",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Order"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/publish/batch": {
+            "post": {
+                "description": "Опубликовать несколько заказов в Kafka. Принимает JSON массив или NDJSON (application/x-ndjson).\nКаждый заказ валидируется отдельно, результат возвращается для каждого заказа.\nX-Correlation-ID и X-Source-System передаются в заголовках всех сообщений пачки.",
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Пакетная публикация заказов",
+                "parameters": [
+                    {
+                        "description": "Orders",
+                        "name": "orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Order"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID для сквозной трассировки заказов",
+                        "name": "X-Correlation-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Система, публикующая заказы",
+                        "name": "X-Source-System",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/http.batchPublishResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/orders": {
+            "get": {
+                "description": "Количество заказов и выручка (сумма payment.amount) по интервалам date_created в UTC.\ngroup_by разбивает каждый интервал по значениям поля заказа. При разбивке по brand заказ\nучитывается в каждом бренде своих товаров, а выручкой считается сумма total_price товаров бренда.\nВыручка суммируется без пересчёта валют, для раздельного учёта используйте group_by=currency.\nПо умолчанию период — последние 30 дней.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Статистика заказов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "day",
+                        "description": "Размер интервала: hour, day или week (неделя с понедельника)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Разбивка: delivery_service, provider, bank, currency, region или brand",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по customer_id",
+                        "name": "customer_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по delivery_service",
+                        "name": "delivery_service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_created не раньше (RFC3339 или YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date_created раньше (RFC3339 или YYYY-MM-DD), по умолчанию — текущее время",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.OrderStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/tracking/{track_number}": {
+            "get": {
+                "description": "Заказы, у которых трек-номер заказа или одного из товаров совпадает с заданным\n(не больше 100, начиная с последних сохранённых).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Заказы по трек-номеру",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Трек-номер",
+                        "name": "track_number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Order"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/problem.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.BrandCount": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.CustomerSummary": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "string"
+                },
+                "first_order_at": {
+                    "type": "string"
+                },
+                "last_order_at": {
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "top_brands": {
+                    "description": "TopBrands — бренды с наибольшим количеством купленных товаров",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BrandCount"
+                    }
+                },
+                "total_spent": {
+                    "description": "TotalSpent — сумма payment.amount по валютам",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                }
+            }
+        },
+        "domain.Delivery": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "zip": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ItemMatch": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "string"
+                },
+                "date_created": {
+                    "type": "string"
+                },
+                "item": {
+                    "$ref": "#/definitions/domain.Items"
+                },
+                "order_uid": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Items": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "chrt_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "nm_id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "rid": {
+                    "type": "string"
+                },
+                "sale": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "total_price": {
+                    "type": "integer"
+                },
+                "track_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Order": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "string"
+                },
+                "date_created": {
+                    "type": "string"
+                },
+                "delivery": {
+                    "$ref": "#/definitions/domain.Delivery"
+                },
+                "delivery_service": {
+                    "type": "string"
+                },
+                "entry": {
+                    "type": "string"
+                },
+                "headers": {
+                    "description": "Headers — заголовки сообщения, с которым заказ пришёл из шины. Заполняются при обработке\nсообщения, значение из тела сообщения не используется.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "internal_signature": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Items"
+                    }
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "oof_shard": {
+                    "type": "string"
+                },
+                "order_uid": {
+                    "type": "string"
+                },
+                "payment": {
+                    "$ref": "#/definitions/domain.Payment"
+                },
+                "shardkey": {
+                    "type": "string"
+                },
+                "sm_id": {
+                    "type": "integer"
+                },
+                "track_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.OrderStats": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "description": "Buckets — непустые интервалы в порядке возрастания Start, внутри интервала — по Group",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.StatsBucket"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "$ref": "#/definitions/domain.StatsGroup"
+                },
+                "interval": {
+                    "$ref": "#/definitions/domain.StatsInterval"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Payment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "bank": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "custom_fee": {
+                    "type": "integer"
+                },
+                "delivery_cost": {
+                    "type": "integer"
+                },
+                "goods_total": {
+                    "type": "integer"
+                },
+                "payment_dt": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "transaction": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SearchPage": {
+            "type": "object",
+            "properties": {
+                "query": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SearchResult"
+                    }
+                },
+                "total": {
+                    "description": "Total — общее количество найденных заказов",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SearchResult": {
+            "type": "object",
+            "properties": {
+                "highlight": {
+                    "description": "Highlight — фрагменты текста заказа с найденными словами в \u003cmark\u003e\u003c/mark\u003e, HTML экранирован",
+                    "type": "string"
+                },
+                "order": {
+                    "$ref": "#/definitions/domain.Order"
+                },
+                "rank": {
+                    "description": "Rank — релевантность заказа запросу, чем больше, тем выше в выдаче",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.StatsBucket": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "description": "Group — значение поля разбивки, пустое без разбивки",
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "description": "Revenue — сумма payment.amount заказов по валютам payment.currency, суммы в разных валютах\nне складываются (для разбивки по брендам — сумма total_price товаров в валюте заказа)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.StatsGroup": {
+            "type": "string",
+            "enum": [
+                "delivery_service",
+                "provider",
+                "bank",
+                "currency",
+                "region",
+                "brand"
+            ],
+            "x-enum-varnames": [
+                "StatsByDeliveryService",
+                "StatsByProvider",
+                "StatsByBank",
+                "StatsByCurrency",
+                "StatsByRegion",
+                "StatsByBrand"
+            ]
+        },
+        "domain.StatsInterval": {
+            "type": "string",
+            "enum": [
+                "hour",
+                "day",
+                "week"
+            ],
+            "x-enum-varnames": [
+                "StatsHour",
+                "StatsDay",
+                "StatsWeek"
+            ]
+        },
+        "feed.Event": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "order": {
+                    "$ref": "#/definitions/domain.Order"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.batchPublishResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.batchPublishResult"
+                    }
+                }
+            }
+        },
+        "http.batchPublishResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "order_uid": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.importResponse": {
+            "type": "object",
+            "properties": {
+                "import_id": {
+                    "type": "string"
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "last_line": {
+                    "description": "LastLine — последняя строка файла, изменения по которой сохранены в БД",
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "rejections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ImportRejection"
+                    }
+                },
+                "rejections_truncated": {
+                    "description": "RejectionsTruncated — в ответ попали не все отклонённые записи",
+                    "type": "boolean"
+                },
+                "resumed_from": {
+                    "description": "ResumedFrom — строка, после которой продолжен прерванный ранее импорт (0 — импорт с начала)",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "Skipped — записи, пропущенные как уже импортированные при продолжении",
+                    "type": "integer"
+                }
+            }
+        },
+        "kafka.ConsumerStatus": {
+            "type": "object",
+            "properties": {
+                "broker_error": {
+                    "description": "BrokerError — ошибка запроса состояния группы у брокеров. Назначения, закоммиченные\noffset'ы и high watermark'и в этом случае могут быть неполными",
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "commit_errors": {
+                    "type": "integer"
+                },
+                "draining": {
+                    "type": "boolean"
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "handle_errors": {
+                    "type": "integer"
+                },
+                "member_id": {
+                    "description": "MemberID — ID участника группы этого экземпляра, пусто, если он сейчас не в группе",
+                    "type": "string"
+                },
+                "partitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kafka.PartitionStatus"
+                    }
+                },
+                "paused": {
+                    "type": "boolean"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "reader": {
+                    "$ref": "#/definitions/kafka.ReaderCounters"
+                },
+                "topic": {
+                    "type": "string"
+                },
+                "total_lag": {
+                    "description": "TotalLag — суммарный лаг назначенных партиций",
+                    "type": "integer"
+                }
+            }
+        },
+        "kafka.PartitionStatus": {
+            "type": "object",
+            "properties": {
+                "assigned": {
+                    "description": "Assigned — партиция назначена этому экземпляру приложения",
+                    "type": "boolean"
+                },
+                "committed_offset": {
+                    "description": "CommittedOffset — закоммиченный группой offset, т.е. следующее сообщение для чтения (-1 — коммитов не было)",
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "Errors — сообщения, обработка которых завершилась ошибкой",
+                    "type": "integer"
+                },
+                "high_watermark": {
+                    "description": "HighWatermark — offset, который получит следующее записанное в партицию сообщение",
+                    "type": "integer"
+                },
+                "lag": {
+                    "description": "Lag — количество сообщений после закоммиченного (или последнего обработанного) offset'а",
+                    "type": "integer"
+                },
+                "last_message_time": {
+                    "type": "string"
+                },
+                "offset": {
+                    "description": "Offset — offset последнего сообщения, обработанного этим экземпляром (-1 — не было)",
+                    "type": "integer"
+                },
+                "partition": {
+                    "type": "integer"
+                },
+                "processed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kafka.ReaderCounters": {
+            "type": "object",
+            "properties": {
+                "bytes": {
+                    "type": "integer"
+                },
+                "dials": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "fetches": {
+                    "type": "integer"
+                },
+                "messages": {
+                    "type": "integer"
+                },
+                "queue_capacity": {
+                    "type": "integer"
+                },
+                "queue_length": {
+                    "type": "integer"
+                },
+                "rebalances": {
+                    "type": "integer"
+                },
+                "timeouts": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kafka.ReplayError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "partition": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kafka.ReplayOptions": {
+            "type": "object",
+            "properties": {
+                "apply": {
+                    "description": "Apply — сохранять заказы через HandleOrder. Иначе выполняется только разбор и валидация (dry-run)",
+                    "type": "boolean"
+                },
+                "dlq": {
+                    "description": "DLQ — читать DLQ топик вместо основного топика заказов",
+                    "type": "boolean"
+                },
+                "from_offset": {
+                    "description": "FromOffset — первый обрабатываемый offset в каждой партиции",
+                    "type": "integer"
+                },
+                "from_time": {
+                    "description": "FromTime — обрабатывать сообщения, записанные не раньше этого времени",
+                    "type": "string"
+                },
+                "partition": {
+                    "description": "Partition — номер партиции, nil — все партиции топика",
+                    "type": "integer"
+                },
+                "to_offset": {
+                    "description": "ToOffset — последний обрабатываемый offset (включительно)",
+                    "type": "integer"
+                },
+                "to_time": {
+                    "description": "ToTime — обрабатывать сообщения, записанные раньше этого времени",
+                    "type": "string"
+                }
+            }
+        },
+        "kafka.ReplayPartitionReport": {
+            "type": "object",
+            "properties": {
+                "end_offset": {
+                    "description": "EndOffset — offset, на котором обработка остановилась (не включительно)",
+                    "type": "integer"
+                },
+                "partition": {
+                    "type": "integer"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "start_offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "kafka.ReplayReport": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "description": "Accepted — заказы, прошедшие валидацию (и сохранённые в режиме apply)",
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kafka.ReplayError"
+                    }
+                },
+                "errors_truncated": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "Failed — сообщения, которые не удалось сохранить",
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "partitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/kafka.ReplayPartitionReport"
+                    }
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "description": "Rejected — сообщения, которые не удалось разобрать или не прошедшие валидацию",
+                    "type": "integer"
+                },
+                "topic": {
+                    "type": "string"
+                }
+            }
+        },
+        "problem.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "instance": {
+                    "description": "Instance — путь запроса, при обработке которого произошла ошибка",
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "RequestID — идентификатор запроса из заголовка X-Request-ID",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type — URI типа ошибки, about:blank означает, что смысл ошибки определяется кодом ответа",
+                    "type": "string"
+                }
+            }
+        },
+        "service.ImportRejection": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
+                },
+                "order_uid": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.ShadowSample": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "description": "Fields — поля верхнего уровня, отличающиеся от сохранённого заказа",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "order_uid": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Reason — причина отклонения или ошибка",
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.ShadowSummary": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "description": "Changed — заказы прошли бы валидацию и перезаписали бы сохранённые",
+                    "type": "integer"
+                },
+                "changed_fields": {
+                    "description": "ChangedFields — сколько раз менялось каждое поле верхнего уровня",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "failed": {
+                    "description": "Failed — не удалось сравнить с сохранёнными данными",
+                    "type": "integer"
+                },
+                "new": {
+                    "description": "New — заказы прошли бы валидацию и были бы сохранены впервые",
+                    "type": "integer"
+                },
+                "processed": {
+                    "type": "integer"
+                },
+                "recent_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ShadowSample"
+                    }
+                },
+                "recent_rejections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.ShadowSample"
+                    }
+                },
+                "rejected": {
+                    "description": "Rejected — сообщения не удалось бы разобрать или они не прошли бы валидацию",
+                    "type": "integer"
+                },
+                "since": {
+                    "type": "string"
+                },
+                "unchanged": {
+                    "description": "Unchanged — заказы прошли бы валидацию и совпадают с сохранёнными",
+                    "type": "integer"
+                }
+            }
+        }
+    }
+}`
+
+// SwaggerInfov1 holds exported Swagger Info so clients can modify it
+var SwaggerInfov1 = &swag.Spec{
+	Version:          "1.0",
+	Host:             "localhost:8080",
+	BasePath:         "/api/v1",
+	Schemes:          []string{},
+	Title:            "WB L0 Go API",
+	Description:      "API for WB L0 Go project",
+	InfoInstanceName: "v1",
+	SwaggerTemplate:  docTemplatev1,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfov1.InstanceName(), SwaggerInfov1)
+}
@@ -8,6 +8,8 @@ import (
 type Cache interface {
 	Put(ctx context.Context, msg domain.Order)
 	Get(ctx context.Context, orderUID string) (domain.Order, bool)
+	// Delete удаляет заказ из кеша, если он там есть.
+	Delete(ctx context.Context, orderUID string)
 }
 
 // OrderLister описывает зависимость, необходимую для предзагрузки кеша
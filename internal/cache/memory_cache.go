@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"log"
+	"slices"
 	"sync"
 
 	"wb-l0-go/internal/domain"
@@ -50,6 +51,17 @@ func (c *MemoryCache) Get(ctx context.Context, orderUID string) (domain.Order, b
 	return order, ok
 }
 
+func (c *MemoryCache) Delete(_ context.Context, orderUID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.ordersMap[orderUID]; !ok {
+		return
+	}
+	delete(c.ordersMap, orderUID)
+	c.order_uids = slices.DeleteFunc(c.order_uids, func(uid string) bool { return uid == orderUID })
+}
+
 func (c *MemoryCache) Load(ctx context.Context, lister OrderLister) {
 	orders, err := lister.ListOrders(ctx, c.maxItems, 0)
 	if err != nil {
@@ -27,3 +27,20 @@ func TestMemoryCache_Put(t *testing.T) {
 	_, exists = cache.Get(context.Background(), "nonexistent")
 	assert.False(t, exists)
 }
+
+func TestMemoryCache_Delete(t *testing.T) {
+	cache := cache.NewMemoryCache(2)
+	ctx := context.Background()
+	cache.Put(ctx, domain.Order{OrderUID: "1"})
+	cache.Put(ctx, domain.Order{OrderUID: "2"})
+
+	cache.Delete(ctx, "1")
+	cache.Delete(ctx, "nonexistent")
+	_, exists := cache.Get(ctx, "1")
+	assert.False(t, exists)
+
+	// Удалённый заказ не занимает место: "2" не вытесняется следующим Put
+	cache.Put(ctx, domain.Order{OrderUID: "3"})
+	_, exists = cache.Get(ctx, "2")
+	assert.True(t, exists)
+}
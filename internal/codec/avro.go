@@ -0,0 +1,137 @@
+package codec
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hamba/avro/v2"
+
+	"wb-l0-go/internal/domain"
+)
+
+// OrderSchemaID — ID встроенной Avro схемы заказа в LocalSchemaRegistry.
+const OrderSchemaID = 1
+
+//go:embed schemas/order.avsc
+var orderSchema string
+
+// Сообщения Avro имеют формат Confluent: нулевой байт, ID схемы писателя (4 байта, big-endian)
+// и тело в бинарной кодировке Avro.
+const (
+	avroMagic     = 0
+	avroHeaderLen = 5
+)
+
+// Имена полей берутся из JSON тегов, поэтому схема совпадает с JSON представлением заказа
+var avroAPI = avro.Config{TagKey: "json"}.Freeze()
+
+// SchemaRegistry возвращает Avro схему по ID.
+type SchemaRegistry interface {
+	Schema(id int) (avro.Schema, error)
+}
+
+// LocalSchemaRegistry — замена schema registry для локального запуска: схемы хранятся в памяти
+// и загружаются из файлов. Встроенная схема заказа всегда зарегистрирована под OrderSchemaID.
+type LocalSchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[int]avro.Schema
+}
+
+func NewLocalSchemaRegistry() *LocalSchemaRegistry {
+	r := &LocalSchemaRegistry{schemas: map[int]avro.Schema{}}
+	if err := r.Register(OrderSchemaID, orderSchema); err != nil {
+		panic(fmt.Sprintf("invalid embedded order schema: %v", err))
+	}
+	return r
+}
+
+// Register разбирает схему и регистрирует её под id, заменяя ранее зарегистрированную.
+func (r *LocalSchemaRegistry) Register(id int, schema string) error {
+	s, err := avro.Parse(schema)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[id] = s
+	return nil
+}
+
+// LoadDir регистрирует схемы из файлов <id>.avsc каталога dir. Остальные файлы пропускаются.
+func (r *LocalSchemaRegistry) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".avsc")
+		if e.IsDir() || !ok {
+			continue
+		}
+		id, err := strconv.Atoi(name)
+		if err != nil {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		if err := r.Register(id, string(raw)); err != nil {
+			return fmt.Errorf("invalid schema %s: %w", e.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (r *LocalSchemaRegistry) Schema(id int) (avro.Schema, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.schemas[id]
+	if !ok {
+		return nil, fmt.Errorf("avro schema %d not found", id)
+	}
+	return s, nil
+}
+
+// avroCodec разбирает сообщение по схеме писателя из заголовка сообщения. Поля схемы,
+// которых нет в заказе, пропускаются, поэтому схема может расширяться без изменения сервиса.
+type avroCodec struct {
+	schemas SchemaRegistry
+}
+
+func (avroCodec) ContentType() string { return ContentTypeAvro }
+
+// Encode кодирует заказ встроенной схемой OrderSchemaID.
+func (c avroCodec) Encode(order domain.Order) ([]byte, error) {
+	schema, err := c.schemas.Schema(OrderSchemaID)
+	if err != nil {
+		return nil, err
+	}
+	body, err := avroAPI.Marshal(schema, order)
+	if err != nil {
+		return nil, err
+	}
+	payload := make([]byte, avroHeaderLen, avroHeaderLen+len(body))
+	binary.BigEndian.PutUint32(payload[1:], OrderSchemaID)
+	return append(payload, body...), nil
+}
+
+func (c avroCodec) Decode(payload []byte) (domain.Order, error) {
+	if len(payload) < avroHeaderLen || payload[0] != avroMagic {
+		return domain.Order{}, errors.New("invalid avro message header")
+	}
+	schema, err := c.schemas.Schema(int(binary.BigEndian.Uint32(payload[1:avroHeaderLen])))
+	if err != nil {
+		return domain.Order{}, err
+	}
+	var order domain.Order
+	err = avroAPI.Unmarshal(schema, payload[avroHeaderLen:], &order)
+	return order, err
+}
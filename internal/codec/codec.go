@@ -0,0 +1,98 @@
+// Package codec кодирует и разбирает заказы в форматах, поддерживаемых шиной сообщений:
+// JSON, protobuf, Avro (со schema registry) и MessagePack. Формат выбирается по
+// заголовку content-type сообщения.
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"wb-l0-go/internal/domain"
+)
+
+// Типы содержимого сообщений с заказами
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeAvro     = "application/avro"
+	ContentTypeMsgPack  = "application/msgpack"
+)
+
+// ErrUnsupportedContentType возвращается, если для типа содержимого нет кодека.
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// Codec кодирует и разбирает заказ в одном формате.
+type Codec interface {
+	ContentType() string
+	Encode(order domain.Order) ([]byte, error)
+	Decode(payload []byte) (domain.Order, error)
+}
+
+// Codecs выбирает кодек по типу содержимого.
+type Codecs struct {
+	byType map[string]Codec
+}
+
+// New создаёт набор кодеков всех поддерживаемых форматов. schemas используется для
+// разбора Avro; если он nil, используется реестр только со встроенной схемой заказа.
+func New(schemas SchemaRegistry) *Codecs {
+	if schemas == nil {
+		schemas = NewLocalSchemaRegistry()
+	}
+	c := &Codecs{byType: map[string]Codec{}}
+	c.register(jsonCodec{})
+	c.register(protobufCodec{}, "application/protobuf", "application/vnd.google.protobuf")
+	c.register(avroCodec{schemas: schemas}, "avro/binary", "application/vnd.apache.avro+binary")
+	c.register(msgpackCodec{}, "application/x-msgpack", "application/vnd.msgpack")
+	return c
+}
+
+func (c *Codecs) register(codec Codec, aliases ...string) {
+	c.byType[codec.ContentType()] = codec
+	for _, alias := range aliases {
+		c.byType[alias] = codec
+	}
+}
+
+// Get возвращает кодек для типа содержимого. Параметры типа (например, charset) не учитываются,
+// пустой тип означает JSON.
+func (c *Codecs) Get(contentType string) (Codec, error) {
+	if strings.TrimSpace(contentType) == "" {
+		return c.byType[ContentTypeJSON], nil
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+	codec, ok := c.byType[mt]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+	return codec, nil
+}
+
+// Decode разбирает заказ в формате contentType.
+func (c *Codecs) Decode(contentType string, payload []byte) (domain.Order, error) {
+	codec, err := c.Get(contentType)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	return codec.Decode(payload)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string { return ContentTypeJSON }
+
+func (jsonCodec) Encode(order domain.Order) ([]byte, error) {
+	return json.Marshal(order)
+}
+
+func (jsonCodec) Decode(payload []byte) (domain.Order, error) {
+	var order domain.Order
+	err := json.Unmarshal(payload, &order)
+	return order, err
+}
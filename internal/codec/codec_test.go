@@ -0,0 +1,100 @@
+package codec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wb-l0-go/internal/domain"
+)
+
+func testOrder() domain.Order {
+	return domain.Order{
+		OrderUID:        "b563feb7b2b84b6test",
+		TrackNumber:     "WBILMTESTTRACK",
+		Entry:           "WBIL",
+		Locale:          "en",
+		CustomerID:      "test",
+		DeliveryService: "meest",
+		ShardKey:        "9",
+		SmID:            99,
+		DateCreated:     time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		OofShard:        "1",
+		Delivery:        domain.Delivery{Name: "Test Testov", Phone: "+9720000000", City: "Kiryat Mozkin", Email: "test@gmail.com"},
+		Payment:         domain.Payment{Transaction: "b563feb7b2b84b6test", Currency: "USD", Provider: "wbpay", Amount: 1817, PaymentDt: 1637907727, Bank: "alpha"},
+		Items: []domain.Items{
+			{ChrtID: 9934930, TrackNumber: "WBILMTESTTRACK", Price: 453, Rid: "ab4219087a764ae0btest", Name: "Mascaras", Sale: 30, Size: "0", TotalPrice: 317, NmID: 2389212, Brand: "Vivienne Sabo", Status: 202},
+		},
+	}
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	codecs := New(nil)
+	order := testOrder()
+
+	for _, contentType := range []string{ContentTypeJSON, ContentTypeProtobuf, ContentTypeAvro, ContentTypeMsgPack} {
+		t.Run(contentType, func(t *testing.T) {
+			c, err := codecs.Get(contentType)
+			require.NoError(t, err)
+			payload, err := c.Encode(order)
+			require.NoError(t, err)
+
+			decoded, err := codecs.Decode(contentType, payload)
+			require.NoError(t, err)
+			assert.True(t, order.DateCreated.Equal(decoded.DateCreated))
+			decoded.DateCreated = order.DateCreated
+			assert.Equal(t, order, decoded)
+		})
+	}
+}
+
+func TestCodecs_Get(t *testing.T) {
+	codecs := New(nil)
+
+	c, err := codecs.Get("")
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJSON, c.ContentType())
+
+	c, err = codecs.Get("application/json; charset=utf-8")
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJSON, c.ContentType())
+
+	c, err = codecs.Get("avro/binary")
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeAvro, c.ContentType())
+
+	_, err = codecs.Get("text/csv")
+	assert.ErrorIs(t, err, ErrUnsupportedContentType)
+}
+
+func TestAvro_WriterSchemaFromRegistry(t *testing.T) {
+	// Схема писателя с дополнительным полем, которого нет в заказе
+	dir := t.TempDir()
+	schema := `{"type":"record","name":"Order","fields":[
+		{"name":"order_uid","type":"string"},
+		{"name":"priority","type":"int"},
+		{"name":"sm_id","type":"long"}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "7.avsc"), []byte(schema), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("skip"), 0o644))
+
+	registry := NewLocalSchemaRegistry()
+	require.NoError(t, registry.LoadDir(dir))
+	writer, err := registry.Schema(7)
+	require.NoError(t, err)
+
+	body, err := avroAPI.Marshal(writer, map[string]any{"order_uid": "order-1", "priority": 3, "sm_id": int64(99)})
+	require.NoError(t, err)
+	payload := append([]byte{0, 0, 0, 0, 7}, body...)
+
+	order, err := New(registry).Decode(ContentTypeAvro, payload)
+	require.NoError(t, err)
+	assert.Equal(t, "order-1", order.OrderUID)
+	assert.Equal(t, 99, order.SmID)
+
+	_, err = New(registry).Decode(ContentTypeAvro, []byte{0, 0, 0, 0, 8})
+	assert.ErrorContains(t, err, "schema 8 not found")
+}
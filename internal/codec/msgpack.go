@@ -0,0 +1,33 @@
+package codec
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"wb-l0-go/internal/domain"
+)
+
+// msgpackCodec использует имена полей из JSON тегов, поэтому структура сообщения
+// совпадает с JSON представлением заказа.
+type msgpackCodec struct{}
+
+func (msgpackCodec) ContentType() string { return ContentTypeMsgPack }
+
+func (msgpackCodec) Encode(order domain.Order) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(order); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Decode(payload []byte) (domain.Order, error) {
+	var order domain.Order
+	dec := msgpack.NewDecoder(bytes.NewReader(payload))
+	dec.SetCustomStructTag("json")
+	err := dec.Decode(&order)
+	return order, err
+}
@@ -0,0 +1,139 @@
+package codec
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"wb-l0-go/internal/domain"
+	orderv1 "wb-l0-go/internal/pb/order/v1"
+)
+
+type protobufCodec struct{}
+
+func (protobufCodec) ContentType() string { return ContentTypeProtobuf }
+
+func (protobufCodec) Encode(order domain.Order) ([]byte, error) {
+	return proto.Marshal(ToProto(order))
+}
+
+func (protobufCodec) Decode(payload []byte) (domain.Order, error) {
+	var msg orderv1.Order
+	if err := proto.Unmarshal(payload, &msg); err != nil {
+		return domain.Order{}, err
+	}
+	return FromProto(&msg), nil
+}
+
+// ToProto переводит заказ в protobuf сообщение order.v1.Order.
+func ToProto(o domain.Order) *orderv1.Order {
+	msg := &orderv1.Order{
+		OrderUid:          o.OrderUID,
+		TrackNumber:       o.TrackNumber,
+		Entry:             o.Entry,
+		Locale:            o.Locale,
+		InternalSignature: o.InternalSig,
+		CustomerId:        o.CustomerID,
+		DeliveryService:   o.DeliveryService,
+		Shardkey:          o.ShardKey,
+		SmId:              int64(o.SmID),
+		OofShard:          o.OofShard,
+		Headers:           o.Headers,
+		Delivery: &orderv1.Delivery{
+			Name:    o.Delivery.Name,
+			Phone:   o.Delivery.Phone,
+			Zip:     o.Delivery.Zip,
+			City:    o.Delivery.City,
+			Address: o.Delivery.Address,
+			Region:  o.Delivery.Region,
+			Email:   o.Delivery.Email,
+		},
+		Payment: &orderv1.Payment{
+			Transaction:  o.Payment.Transaction,
+			RequestId:    o.Payment.RequestId,
+			Currency:     o.Payment.Currency,
+			Provider:     o.Payment.Provider,
+			Amount:       int64(o.Payment.Amount),
+			PaymentDt:    int64(o.Payment.PaymentDt),
+			Bank:         o.Payment.Bank,
+			DeliveryCost: int64(o.Payment.DeliveryCost),
+			GoodsTotal:   int64(o.Payment.GoodsTotal),
+			CustomFee:    int64(o.Payment.CustomFee),
+		},
+	}
+	if !o.DateCreated.IsZero() {
+		msg.DateCreated = timestamppb.New(o.DateCreated)
+	}
+	for _, it := range o.Items {
+		msg.Items = append(msg.Items, &orderv1.Item{
+			ChrtId:      int64(it.ChrtID),
+			TrackNumber: it.TrackNumber,
+			Price:       int64(it.Price),
+			Rid:         it.Rid,
+			Name:        it.Name,
+			Sale:        int64(it.Sale),
+			Size:        it.Size,
+			TotalPrice:  int64(it.TotalPrice),
+			NmId:        int64(it.NmID),
+			Brand:       it.Brand,
+			Status:      int64(it.Status),
+		})
+	}
+	return msg
+}
+
+// FromProto переводит protobuf сообщение order.v1.Order в заказ.
+func FromProto(msg *orderv1.Order) domain.Order {
+	o := domain.Order{
+		OrderUID:        msg.GetOrderUid(),
+		TrackNumber:     msg.GetTrackNumber(),
+		Entry:           msg.GetEntry(),
+		Locale:          msg.GetLocale(),
+		InternalSig:     msg.GetInternalSignature(),
+		CustomerID:      msg.GetCustomerId(),
+		DeliveryService: msg.GetDeliveryService(),
+		ShardKey:        msg.GetShardkey(),
+		SmID:            int(msg.GetSmId()),
+		OofShard:        msg.GetOofShard(),
+		Headers:         msg.GetHeaders(),
+		Delivery: domain.Delivery{
+			Name:    msg.GetDelivery().GetName(),
+			Phone:   msg.GetDelivery().GetPhone(),
+			Zip:     msg.GetDelivery().GetZip(),
+			City:    msg.GetDelivery().GetCity(),
+			Address: msg.GetDelivery().GetAddress(),
+			Region:  msg.GetDelivery().GetRegion(),
+			Email:   msg.GetDelivery().GetEmail(),
+		},
+		Payment: domain.Payment{
+			Transaction:  msg.GetPayment().GetTransaction(),
+			RequestId:    msg.GetPayment().GetRequestId(),
+			Currency:     msg.GetPayment().GetCurrency(),
+			Provider:     msg.GetPayment().GetProvider(),
+			Amount:       int(msg.GetPayment().GetAmount()),
+			PaymentDt:    int(msg.GetPayment().GetPaymentDt()),
+			Bank:         msg.GetPayment().GetBank(),
+			DeliveryCost: int(msg.GetPayment().GetDeliveryCost()),
+			GoodsTotal:   int(msg.GetPayment().GetGoodsTotal()),
+			CustomFee:    int(msg.GetPayment().GetCustomFee()),
+		},
+	}
+	if msg.GetDateCreated() != nil {
+		o.DateCreated = msg.GetDateCreated().AsTime()
+	}
+	for _, it := range msg.GetItems() {
+		o.Items = append(o.Items, domain.Items{
+			ChrtID:      int(it.GetChrtId()),
+			TrackNumber: it.GetTrackNumber(),
+			Price:       int(it.GetPrice()),
+			Rid:         it.GetRid(),
+			Name:        it.GetName(),
+			Sale:        int(it.GetSale()),
+			Size:        it.GetSize(),
+			TotalPrice:  int(it.GetTotalPrice()),
+			NmID:        int(it.GetNmId()),
+			Brand:       it.GetBrand(),
+			Status:      int(it.GetStatus()),
+		})
+	}
+	return o
+}
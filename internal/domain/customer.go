@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// BrandCount — сколько заказов и товаров покупателя приходится на бренд.
+type BrandCount struct {
+	Brand  string `json:"brand"`
+	Orders int64  `json:"orders"`
+	Items  int64  `json:"items"`
+}
+
+// CustomerSummary — сводка по заказам покупателя.
+type CustomerSummary struct {
+	CustomerID string `json:"customer_id"`
+	Orders     int64  `json:"orders"`
+	// TotalSpent — сумма payment.amount по валютам
+	TotalSpent   map[string]int64 `json:"total_spent"`
+	FirstOrderAt time.Time        `json:"first_order_at"`
+	LastOrderAt  time.Time        `json:"last_order_at"`
+	// TopBrands — бренды с наибольшим количеством купленных товаров
+	TopBrands []BrandCount `json:"top_brands"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "errors"
+
+// Ошибки, которые репозитории и сервисы возвращают вместо ошибок драйверов и библиотек.
+// Транспортный слой сопоставляет им коды ответа, проверяя их через errors.Is.
+var (
+	// ErrNotFound — запрошенный объект не существует
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidArgument — некорректные параметры запроса
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrConflict — операция противоречит текущему состоянию
+	ErrConflict = errors.New("conflict")
+)
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// ItemQuery — условия поиска товаров. Товар должен совпасть по всем заданным полям,
+// пустые (нулевые) поля не участвуют в поиске.
+type ItemQuery struct {
+	Rid    string
+	NmID   int
+	ChrtID int
+}
+
+// Empty сообщает, что не задано ни одно условие.
+func (q ItemQuery) Empty() bool {
+	return q == ItemQuery{}
+}
+
+// ItemMatch — найденный товар вместе с данными заказа, в котором он находится.
+type ItemMatch struct {
+	OrderUID    string    `json:"order_uid"`
+	TrackNumber string    `json:"track_number"`
+	CustomerID  string    `json:"customer_id"`
+	DateCreated time.Time `json:"date_created"`
+	Item        Items     `json:"item"`
+}
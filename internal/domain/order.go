@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Delivery struct {
 	Name    string `json:"name"`
@@ -54,4 +57,67 @@ type Order struct {
 	SmID            int       `json:"sm_id"`
 	DateCreated     time.Time `json:"date_created"`
 	OofShard        string    `json:"oof_shard"`
+	// Headers — заголовки сообщения, с которым заказ пришёл из шины. Заполняются при обработке
+	// сообщения, значение из тела сообщения не используется.
+	Headers map[string]string `json:"headers,omitempty"`
+	// UpdatedAt — время последнего сохранения заказа в БД. Не входит в payload,
+	// используется для Last-Modified.
+	UpdatedAt time.Time `json:"-"`
+}
+
+// Заголовки сообщений с заказами
+const (
+	// HeaderCorrelationID — ID для сквозной трассировки заказа между системами
+	HeaderCorrelationID = "correlation-id"
+	// HeaderSourceSystem — система, опубликовавшая заказ
+	HeaderSourceSystem = "source-system"
+	// HeaderContentType — формат тела сообщения
+	HeaderContentType = "content-type"
+	// HeaderRequestID — ID запроса, по которому опубликован заказ, для связи логов публикации и обработки
+	HeaderRequestID = "request-id"
+)
+
+// Envelope — сообщение с заказом, прочитанное из шины: ключ, тело и заголовки.
+type Envelope struct {
+	Key     string
+	Payload []byte
+	Headers map[string]string
+}
+
+// Header возвращает значение заголовка name. Имена заголовков Kafka и NATS чувствительны
+// к регистру, поэтому при отсутствии точного совпадения имя сравнивается без учёта регистра.
+func (e Envelope) Header(name string) string {
+	if v, ok := e.Headers[name]; ok {
+		return v
+	}
+	for k, v := range e.Headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// OrderFilter описывает условия отбора заказов при получении списка и выгрузке.
+// Пустые поля не участвуют в отборе.
+type OrderFilter struct {
+	CustomerID      string
+	DeliveryService string
+	// Границы по date_created: From включительно, To не включительно
+	From time.Time
+	To   time.Time
+}
+
+// EventOrderStored — тип события о сохранении заказа.
+const EventOrderStored = "order.stored"
+
+// OrderEvent — событие об изменении заказа, публикуемое в топик событий через outbox.
+type OrderEvent struct {
+	// EventID — уникальный ID события. Доставка at-least-once, поэтому получатели
+	// должны отбрасывать повторы по этому полю.
+	EventID    string    `json:"event_id"`
+	Type       string    `json:"type"`
+	OrderUID   string    `json:"order_uid"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Order      Order     `json:"order"`
 }
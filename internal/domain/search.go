@@ -0,0 +1,18 @@
+package domain
+
+// SearchResult — заказ, найденный полнотекстовым поиском.
+type SearchResult struct {
+	Order Order `json:"order"`
+	// Rank — релевантность заказа запросу, чем больше, тем выше в выдаче
+	Rank float32 `json:"rank"`
+	// Highlight — фрагменты текста заказа с найденными словами в <mark></mark>, HTML экранирован
+	Highlight string `json:"highlight"`
+}
+
+// SearchPage — страница результатов поиска.
+type SearchPage struct {
+	Query string `json:"query"`
+	// Total — общее количество найденных заказов
+	Total   int64          `json:"total"`
+	Results []SearchResult `json:"results"`
+}
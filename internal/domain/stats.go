@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+// StatsInterval — размер интервала, по которым группируется статистика заказов.
+type StatsInterval string
+
+const (
+	StatsHour StatsInterval = "hour"
+	StatsDay  StatsInterval = "day"
+	// StatsWeek — неделя ISO, начинается с понедельника
+	StatsWeek StatsInterval = "week"
+)
+
+// Duration возвращает длительность интервала и false для неизвестного интервала.
+func (i StatsInterval) Duration() (time.Duration, bool) {
+	switch i {
+	case StatsHour:
+		return time.Hour, true
+	case StatsDay:
+		return 24 * time.Hour, true
+	case StatsWeek:
+		return 7 * 24 * time.Hour, true
+	}
+	return 0, false
+}
+
+// StatsGroup — поле заказа, по значениям которого статистика разбивается внутри интервала.
+type StatsGroup string
+
+const (
+	StatsByDeliveryService StatsGroup = "delivery_service"
+	StatsByProvider        StatsGroup = "provider"
+	StatsByBank            StatsGroup = "bank"
+	StatsByCurrency        StatsGroup = "currency"
+	StatsByRegion          StatsGroup = "region"
+	// StatsByBrand разбивает по брендам товаров: заказ учитывается в каждом бренде,
+	// товары которого в нём есть, а выручкой считается сумма total_price товаров бренда
+	StatsByBrand StatsGroup = "brand"
+)
+
+// StatsGroups — допустимые значения StatsGroup.
+var StatsGroups = []StatsGroup{
+	StatsByDeliveryService, StatsByProvider, StatsByBank, StatsByCurrency, StatsByRegion, StatsByBrand,
+}
+
+// StatsQuery описывает запрос статистики заказов. Пустой GroupBy — без разбивки.
+type StatsQuery struct {
+	Filter   OrderFilter
+	Interval StatsInterval
+	GroupBy  StatsGroup
+}
+
+// StatsBucket — количество заказов и выручка за интервал, начинающийся в Start (UTC).
+type StatsBucket struct {
+	Start time.Time `json:"start"`
+	// Group — значение поля разбивки, пустое без разбивки
+	Group  string `json:"group,omitempty"`
+	Orders int64  `json:"orders"`
+	// Revenue — сумма payment.amount заказов по валютам payment.currency, суммы в разных валютах
+	// не складываются (для разбивки по брендам — сумма total_price товаров в валюте заказа)
+	Revenue map[string]int64 `json:"revenue"`
+}
+
+// OrderStats — статистика заказов за период [From, To).
+type OrderStats struct {
+	Interval StatsInterval `json:"interval"`
+	GroupBy  StatsGroup    `json:"group_by,omitempty"`
+	From     time.Time     `json:"from"`
+	To       time.Time     `json:"to"`
+	// Buckets — непустые интервалы в порядке возрастания Start, внутри интервала — по Group
+	Buckets []StatsBucket `json:"buckets"`
+}
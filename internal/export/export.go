@@ -0,0 +1,117 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"wb-l0-go/internal/domain"
+)
+
+// Format — формат выгрузки заказов.
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatXLSX   Format = "xlsx"
+)
+
+// ParseFormat проверяет название формата выгрузки.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatCSV, FormatNDJSON, FormatXLSX:
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q", s)
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+// Writer пишет заказы в выбранном формате. Close дописывает окончание файла
+// и должен быть вызван после записи всех заказов.
+type Writer interface {
+	Write(order domain.Order) error
+	Close() error
+}
+
+func NewWriter(f Format, w io.Writer) (Writer, error) {
+	switch f {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported export format %q", f)
+}
+
+// Columns — заголовки колонок табличных форматов. Каждая строка таблицы
+// соответствует одному товару заказа, поля заказа повторяются для всех его товаров.
+var Columns = []string{
+	"order_uid", "track_number", "entry", "locale", "internal_signature", "customer_id",
+	"delivery_service", "shardkey", "sm_id", "date_created", "oof_shard",
+	"delivery_name", "delivery_phone", "delivery_zip", "delivery_city", "delivery_address",
+	"delivery_region", "delivery_email",
+	"payment_transaction", "payment_request_id", "payment_currency", "payment_provider",
+	"payment_amount", "payment_dt", "payment_bank", "payment_delivery_cost",
+	"payment_goods_total", "payment_custom_fee",
+	"item_chrt_id", "item_track_number", "item_price", "item_rid", "item_name", "item_sale",
+	"item_size", "item_total_price", "item_nm_id", "item_brand", "item_status",
+}
+
+// Rows разворачивает заказ в строки таблицы: по одной на каждый товар.
+// Заказ без товаров даёт одну строку с пустыми колонками товара.
+// Значения имеют тип string или int.
+func Rows(o domain.Order) [][]any {
+	base := []any{
+		o.OrderUID, o.TrackNumber, o.Entry, o.Locale, o.InternalSig, o.CustomerID,
+		o.DeliveryService, o.ShardKey, o.SmID, o.DateCreated.Format(time.RFC3339), o.OofShard,
+		o.Delivery.Name, o.Delivery.Phone, o.Delivery.Zip, o.Delivery.City, o.Delivery.Address,
+		o.Delivery.Region, o.Delivery.Email,
+		o.Payment.Transaction, o.Payment.RequestId, o.Payment.Currency, o.Payment.Provider,
+		o.Payment.Amount, o.Payment.PaymentDt, o.Payment.Bank, o.Payment.DeliveryCost,
+		o.Payment.GoodsTotal, o.Payment.CustomFee,
+	}
+
+	if len(o.Items) == 0 {
+		row := make([]any, len(Columns))
+		copy(row, base)
+		for i := len(base); i < len(row); i++ {
+			row[i] = ""
+		}
+		return [][]any{row}
+	}
+
+	rows := make([][]any, 0, len(o.Items))
+	for _, it := range o.Items {
+		row := append(base[:len(base):len(base)],
+			it.ChrtID, it.TrackNumber, it.Price, it.Rid, it.Name, it.Sale,
+			it.Size, it.TotalPrice, it.NmID, it.Brand, it.Status,
+		)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	}
+	return fmt.Sprint(v)
+}
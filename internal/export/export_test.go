@@ -0,0 +1,121 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/export"
+)
+
+func testOrder(uid string, items ...string) domain.Order {
+	order := domain.Order{
+		OrderUID:    uid,
+		CustomerID:  "customer",
+		DateCreated: time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		Delivery:    domain.Delivery{Name: "Test Testov"},
+		Payment:     domain.Payment{Amount: 1817},
+	}
+	for _, name := range items {
+		order.Items = append(order.Items, domain.Items{Name: name, Price: 453})
+	}
+	return order
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := export.ParseFormat("xlsx")
+	require.NoError(t, err)
+	assert.Equal(t, export.FormatXLSX, f)
+
+	_, err = export.ParseFormat("pdf")
+	assert.Error(t, err)
+}
+
+func TestCSVWriter_OneRowPerItem(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := export.NewWriter(export.FormatCSV, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(testOrder("order-1", "Mascaras", "Lipstick")))
+	require.NoError(t, w.Write(testOrder("order-2")))
+	require.NoError(t, w.Close())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	// Заголовок, два товара первого заказа и одна строка заказа без товаров
+	require.Len(t, records, 4)
+	assert.Equal(t, export.Columns, records[0])
+	assert.Equal(t, "order-1", records[1][0])
+	assert.Equal(t, "order-1", records[2][0])
+	assert.Equal(t, "order-2", records[3][0])
+
+	nameCol := indexOf(export.Columns, "item_name")
+	assert.Equal(t, "Mascaras", records[1][nameCol])
+	assert.Equal(t, "Lipstick", records[2][nameCol])
+	assert.Equal(t, "", records[3][nameCol])
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := export.NewWriter(export.FormatNDJSON, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(testOrder("order-1", "Mascaras")))
+	require.NoError(t, w.Write(testOrder("order-2")))
+	require.NoError(t, w.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var order domain.Order
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &order))
+	assert.Equal(t, "order-1", order.OrderUID)
+}
+
+func TestXLSXWriter_ProducesWorkbook(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := export.NewWriter(export.FormatXLSX, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(testOrder("order-<1>", "Mascaras")))
+	require.NoError(t, w.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		body, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(body)
+	}
+	assert.Contains(t, files, "[Content_Types].xml")
+	assert.Contains(t, files, "xl/workbook.xml")
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row r="2">`)
+	// Спецсимволы XML экранируются, числа пишутся числовыми ячейками
+	assert.Contains(t, sheet, "order-&lt;1&gt;")
+	assert.Contains(t, sheet, "<c><v>1817</v></c>")
+	assert.True(t, strings.HasSuffix(sheet, "</sheetData></worksheet>"))
+}
+
+func indexOf(values []string, v string) int {
+	for i, s := range values {
+		if s == v {
+			return i
+		}
+	}
+	return -1
+}
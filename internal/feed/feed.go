@@ -0,0 +1,102 @@
+package feed
+
+import (
+	"sync"
+	"time"
+
+	"wb-l0-go/internal/domain"
+)
+
+// Размер буфера канала подписчика. Если подписчик не успевает вычитывать события,
+// он отключается и может переподключиться, указав ID последнего полученного события.
+const subscriberBuffer = 64
+
+// Event — событие о новом сохранённом заказе.
+type Event struct {
+	ID    uint64       `json:"id"`
+	Time  time.Time    `json:"time"`
+	Order domain.Order `json:"order"`
+}
+
+// Feed рассылает события о новых заказах подписчикам и хранит последние события
+// в кольцевом буфере, чтобы переподключившийся клиент мог продолжить с места обрыва.
+type Feed struct {
+	mu      sync.Mutex
+	lastID  uint64
+	history []Event
+	start   int // индекс самого старого события в history
+	size    int // количество событий в history
+	subs    map[chan Event]struct{}
+}
+
+func New(historySize int) *Feed {
+	if historySize <= 0 {
+		historySize = 1000
+	}
+	return &Feed{
+		// ID начинаются с текущего времени, чтобы после перезапуска приложения
+		// клиенты со старым Last-Event-ID не пропускали новые события
+		lastID:  uint64(time.Now().UnixNano()),
+		history: make([]Event, historySize),
+		subs:    make(map[chan Event]struct{}),
+	}
+}
+
+// Publish сохраняет событие в истории и рассылает его подписчикам.
+func (f *Feed) Publish(order domain.Order) Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastID++
+	ev := Event{ID: f.lastID, Time: time.Now().UTC(), Order: order}
+
+	idx := (f.start + f.size) % len(f.history)
+	f.history[idx] = ev
+	if f.size < len(f.history) {
+		f.size++
+	} else {
+		f.start = (f.start + 1) % len(f.history)
+	}
+
+	for ch := range f.subs {
+		select {
+		case ch <- ev:
+		default:
+			// Медленный подписчик: отключаем, он переподключится с Last-Event-ID
+			delete(f.subs, ch)
+			close(ch)
+		}
+	}
+	return ev
+}
+
+// Subscribe подписывается на новые события. Если lastID не равен нулю, возвращает
+// из истории события с ID больше lastID. Канал закрывается при отписке
+// или если подписчик не успевает вычитывать события.
+func (f *Feed) Subscribe(lastID uint64) ([]Event, <-chan Event, func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var backlog []Event
+	if lastID != 0 && lastID < f.lastID {
+		for i := 0; i < f.size; i++ {
+			ev := f.history[(f.start+i)%len(f.history)]
+			if ev.ID > lastID {
+				backlog = append(backlog, ev)
+			}
+		}
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	f.subs[ch] = struct{}{}
+
+	cancel := func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.subs[ch]; ok {
+			delete(f.subs, ch)
+			close(ch)
+		}
+	}
+	return backlog, ch, cancel
+}
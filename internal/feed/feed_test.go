@@ -0,0 +1,64 @@
+package feed_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/feed"
+)
+
+func TestFeed_PublishSubscribe(t *testing.T) {
+	f := feed.New(10)
+
+	backlog, events, cancel := f.Subscribe(0)
+	defer cancel()
+	assert.Empty(t, backlog)
+
+	ev := f.Publish(domain.Order{OrderUID: "order-1"})
+
+	got := <-events
+	assert.Equal(t, ev.ID, got.ID)
+	assert.Equal(t, "order-1", got.Order.OrderUID)
+}
+
+func TestFeed_ResumeFromLastEventID(t *testing.T) {
+	f := feed.New(3)
+
+	first := f.Publish(domain.Order{OrderUID: "order-1"})
+	f.Publish(domain.Order{OrderUID: "order-2"})
+	f.Publish(domain.Order{OrderUID: "order-3"})
+	f.Publish(domain.Order{OrderUID: "order-4"})
+
+	// В истории остаются только 3 последних события
+	backlog, _, cancel := f.Subscribe(first.ID)
+	defer cancel()
+	require.Len(t, backlog, 3)
+	assert.Equal(t, "order-2", backlog[0].Order.OrderUID)
+	assert.Equal(t, "order-4", backlog[2].Order.OrderUID)
+
+	// Клиент, получивший все события, не получает ничего из истории
+	backlog, _, cancel2 := f.Subscribe(backlog[2].ID)
+	defer cancel2()
+	assert.Empty(t, backlog)
+}
+
+func TestFeed_SlowSubscriberIsDisconnected(t *testing.T) {
+	f := feed.New(10)
+
+	_, events, cancel := f.Subscribe(0)
+	defer cancel()
+
+	// Подписчик ничего не читает, пока буфер не переполнится
+	for i := 0; i < 100; i++ {
+		f.Publish(domain.Order{OrderUID: "order"})
+	}
+
+	n := 0
+	for range events {
+		n++
+	}
+	assert.Less(t, n, 100)
+}
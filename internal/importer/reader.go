@@ -0,0 +1,285 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/export"
+)
+
+// Record — заказ, прочитанный из файла импорта.
+type Record struct {
+	// Line — номер последней строки файла, относящейся к заказу (нумерация с 1).
+	// Используется как позиция для продолжения прерванного импорта.
+	Line  int
+	Order domain.Order
+	// Err — ошибка разбора записи. Такая запись попадает в отчёт об отклонённых строках.
+	Err error
+}
+
+// Reader последовательно читает заказы из файла импорта.
+// Next возвращает io.EOF, когда записи закончились.
+type Reader interface {
+	Next() (Record, error)
+}
+
+// NewReader создаёт Reader для формата NDJSON или CSV.
+// CSV должен иметь заголовок с колонками из export.Columns, строки одного заказа
+// (по одной на товар) должны идти подряд — как в выгрузке GET /orders/export.
+func NewReader(format export.Format, r io.Reader) (Reader, error) {
+	switch format {
+	case export.FormatNDJSON:
+		return newNDJSONReader(r), nil
+	case export.FormatCSV:
+		return newCSVReader(r)
+	}
+	return nil, fmt.Errorf("unsupported import format %q", format)
+}
+
+// Максимальная длина строки NDJSON
+const maxLineBytes = 16 << 20
+
+type ndjsonReader struct {
+	sc   *bufio.Scanner
+	line int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	return &ndjsonReader{sc: sc}
+}
+
+func (n *ndjsonReader) Next() (Record, error) {
+	for n.sc.Scan() {
+		n.line++
+		line := bytes.TrimSpace(n.sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rec := Record{Line: n.line}
+		if err := json.Unmarshal(line, &rec.Order); err != nil {
+			rec.Err = fmt.Errorf("invalid JSON: %w", err)
+		}
+		return rec, nil
+	}
+	if err := n.sc.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+	// Первая строка следующего заказа, уже прочитанная из файла
+	pending []string
+	line    int
+	pendErr error
+	eof     bool
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	if _, ok := columns["order_uid"]; !ok {
+		return nil, errors.New("CSV header must contain order_uid column")
+	}
+	return &csvReader{r: cr, columns: columns}, nil
+}
+
+func (c *csvReader) read() {
+	row, err := c.r.Read()
+	if err == io.EOF {
+		c.pending, c.eof = nil, true
+		return
+	}
+	var parseErr *csv.ParseError
+	switch {
+	case err == nil:
+		c.line, _ = c.r.FieldPos(0)
+	case errors.As(err, &parseErr):
+		c.line = parseErr.Line
+	}
+	c.pending, c.pendErr = row, err
+}
+
+func (c *csvReader) Next() (Record, error) {
+	if c.pending == nil && c.pendErr == nil {
+		if c.eof {
+			return Record{}, io.EOF
+		}
+		c.read()
+		if c.eof {
+			return Record{}, io.EOF
+		}
+	}
+	// Битая строка CSV становится отдельной отклонённой записью
+	if c.pendErr != nil {
+		var parseErr *csv.ParseError
+		if !errors.As(c.pendErr, &parseErr) {
+			return Record{}, c.pendErr
+		}
+		rec := Record{Line: c.line, Err: fmt.Errorf("invalid CSV: %w", c.pendErr)}
+		c.pending, c.pendErr = nil, nil
+		return rec, nil
+	}
+
+	rec := Record{}
+	first := c.pending
+	rec.Order, rec.Err = c.parseOrder(first)
+	rec.Line = c.line
+	uid := c.value(first, "order_uid")
+
+	// Собираем товары из последующих строк того же заказа
+	for {
+		c.read()
+		if c.eof || c.pendErr != nil || c.value(c.pending, "order_uid") != uid {
+			return rec, nil
+		}
+		rec.Line = c.line
+		if rec.Err != nil {
+			continue
+		}
+		item, ok, err := c.parseItem(c.pending)
+		if err != nil {
+			rec.Err = err
+			continue
+		}
+		if ok {
+			rec.Order.Items = append(rec.Order.Items, item)
+		}
+	}
+}
+
+func (c *csvReader) value(row []string, column string) string {
+	i, ok := c.columns[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return row[i]
+}
+
+// fieldParser накапливает первую ошибку разбора числовых и временных полей строки.
+type fieldParser struct {
+	c   *csvReader
+	row []string
+	err error
+}
+
+func (p *fieldParser) str(column string) string {
+	return p.c.value(p.row, column)
+}
+
+func (p *fieldParser) int(column string) int {
+	s := p.c.value(p.row, column)
+	if s == "" || p.err != nil {
+		return 0
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		p.err = fmt.Errorf("invalid %s: %q is not an integer", column, s)
+	}
+	return n
+}
+
+func (p *fieldParser) time(column string) time.Time {
+	s := p.c.value(p.row, column)
+	if s == "" || p.err != nil {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		p.err = fmt.Errorf("invalid %s: %q is not RFC3339 time", column, s)
+	}
+	return t
+}
+
+func (c *csvReader) parseOrder(row []string) (domain.Order, error) {
+	p := &fieldParser{c: c, row: row}
+	order := domain.Order{
+		OrderUID:        p.str("order_uid"),
+		TrackNumber:     p.str("track_number"),
+		Entry:           p.str("entry"),
+		Locale:          p.str("locale"),
+		InternalSig:     p.str("internal_signature"),
+		CustomerID:      p.str("customer_id"),
+		DeliveryService: p.str("delivery_service"),
+		ShardKey:        p.str("shardkey"),
+		SmID:            p.int("sm_id"),
+		DateCreated:     p.time("date_created"),
+		OofShard:        p.str("oof_shard"),
+		Delivery: domain.Delivery{
+			Name:    p.str("delivery_name"),
+			Phone:   p.str("delivery_phone"),
+			Zip:     p.str("delivery_zip"),
+			City:    p.str("delivery_city"),
+			Address: p.str("delivery_address"),
+			Region:  p.str("delivery_region"),
+			Email:   p.str("delivery_email"),
+		},
+		Payment: domain.Payment{
+			Transaction:  p.str("payment_transaction"),
+			RequestId:    p.str("payment_request_id"),
+			Currency:     p.str("payment_currency"),
+			Provider:     p.str("payment_provider"),
+			Amount:       p.int("payment_amount"),
+			PaymentDt:    p.int("payment_dt"),
+			Bank:         p.str("payment_bank"),
+			DeliveryCost: p.int("payment_delivery_cost"),
+			GoodsTotal:   p.int("payment_goods_total"),
+			CustomFee:    p.int("payment_custom_fee"),
+		},
+	}
+	if p.err != nil {
+		return order, p.err
+	}
+
+	item, ok, err := c.parseItem(row)
+	if err != nil {
+		return order, err
+	}
+	if ok {
+		order.Items = append(order.Items, item)
+	}
+	return order, nil
+}
+
+// parseItem читает товар из строки. ok == false, если колонки товара пусты
+// (заказ без товаров в выгрузке).
+func (c *csvReader) parseItem(row []string) (domain.Items, bool, error) {
+	p := &fieldParser{c: c, row: row}
+	item := domain.Items{
+		ChrtID:      p.int("item_chrt_id"),
+		TrackNumber: p.str("item_track_number"),
+		Price:       p.int("item_price"),
+		Rid:         p.str("item_rid"),
+		Name:        p.str("item_name"),
+		Sale:        p.int("item_sale"),
+		Size:        p.str("item_size"),
+		TotalPrice:  p.int("item_total_price"),
+		NmID:        p.int("item_nm_id"),
+		Brand:       p.str("item_brand"),
+		Status:      p.int("item_status"),
+	}
+	if p.err != nil {
+		return domain.Items{}, false, p.err
+	}
+	return item, item != domain.Items{}, nil
+}
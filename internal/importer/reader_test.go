@@ -0,0 +1,101 @@
+package importer_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/export"
+	"wb-l0-go/internal/importer"
+)
+
+func readAll(t *testing.T, r importer.Reader) []importer.Record {
+	t.Helper()
+	var records []importer.Record
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(t, err)
+		records = append(records, rec)
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	body := "{\"order_uid\":\"a\"}\n\n{broken\n{\"order_uid\":\"c\"}\n"
+
+	r, err := importer.NewReader(export.FormatNDJSON, strings.NewReader(body))
+	require.NoError(t, err)
+	records := readAll(t, r)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, 1, records[0].Line)
+	assert.Equal(t, "a", records[0].Order.OrderUID)
+	// Пустая строка пропускается, но учитывается в нумерации
+	assert.Equal(t, 3, records[1].Line)
+	assert.Error(t, records[1].Err)
+	assert.Equal(t, 4, records[2].Line)
+}
+
+func TestCSVReader_RoundTripWithExport(t *testing.T) {
+	order := domain.Order{
+		OrderUID:    "order-1",
+		TrackNumber: "WBILMTESTTRACK",
+		CustomerID:  "test",
+		SmID:        99,
+		DateCreated: time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		Delivery:    domain.Delivery{Name: "Test Testov", City: "Kiryat Mozkin"},
+		Payment:     domain.Payment{Transaction: "b563feb7b2b84b6test", Amount: 1817},
+		Items: []domain.Items{
+			{ChrtID: 9934930, Name: "Mascaras", Price: 453, Brand: "Vivienne Sabo"},
+			{ChrtID: 9934931, Name: "Lipstick", Price: 300, Brand: "Vivienne Sabo"},
+		},
+	}
+
+	var buf bytes.Buffer
+	w, err := export.NewWriter(export.FormatCSV, &buf)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(order))
+	require.NoError(t, w.Write(domain.Order{OrderUID: "order-2", DateCreated: order.DateCreated}))
+	require.NoError(t, w.Close())
+
+	r, err := importer.NewReader(export.FormatCSV, &buf)
+	require.NoError(t, err)
+	records := readAll(t, r)
+	require.Len(t, records, 2)
+
+	require.NoError(t, records[0].Err)
+	assert.Equal(t, order, records[0].Order)
+	// Строка заказа заканчивается на строке второго товара
+	assert.Equal(t, 3, records[0].Line)
+
+	require.NoError(t, records[1].Err)
+	assert.Equal(t, "order-2", records[1].Order.OrderUID)
+	assert.Empty(t, records[1].Order.Items)
+	assert.Equal(t, 4, records[1].Line)
+}
+
+func TestCSVReader_InvalidValue(t *testing.T) {
+	body := "order_uid,sm_id\norder-1,abc\norder-2,5\n"
+
+	r, err := importer.NewReader(export.FormatCSV, strings.NewReader(body))
+	require.NoError(t, err)
+	records := readAll(t, r)
+	require.Len(t, records, 2)
+
+	assert.ErrorContains(t, records[0].Err, "sm_id")
+	assert.NoError(t, records[1].Err)
+	assert.Equal(t, 5, records[1].Order.SmID)
+}
+
+func TestCSVReader_RequiresOrderUIDColumn(t *testing.T) {
+	_, err := importer.NewReader(export.FormatCSV, strings.NewReader("track_number\nX\n"))
+	assert.Error(t, err)
+}
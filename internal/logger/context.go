@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type fieldsKey struct{}
+
+// WithFields возвращает контекст, к логам которого добавляются поля fields
+// (например, request_id). Поля накапливаются при повторных вызовах.
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	prev, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	all := make([]zap.Field, 0, len(prev)+len(fields))
+	all = append(append(all, prev...), fields...)
+	return context.WithValue(ctx, fieldsKey{}, all)
+}
+
+// FromContext возвращает log с полями, сохранёнными в ctx через WithFields.
+// Используется везде, где логируется обработка запроса или сообщения, чтобы записи
+// обработчика, сервиса и consumer'а можно было связать по request_id.
+func FromContext(ctx context.Context, log *zap.Logger) *zap.Logger {
+	fields, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	if len(fields) == 0 {
+		return log
+	}
+	return log.With(fields...)
+}
@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFromContext(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	base := zap.New(core).With(zap.String("component", "test"))
+
+	FromContext(context.Background(), base).Info("no fields")
+
+	ctx := WithFields(context.Background(), zap.String("request_id", "req-1"))
+	ctx = WithFields(ctx, zap.String("correlation_id", "corr-1"))
+	FromContext(ctx, base).Info("with fields")
+
+	entries := logs.All()
+	assert.Equal(t, map[string]any{"component": "test"}, entries[0].ContextMap())
+	assert.Equal(t, map[string]any{
+		"component":      "test",
+		"request_id":     "req-1",
+		"correlation_id": "corr-1",
+	}, entries[1].ContextMap())
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ImportCheckpointRepository хранит позицию, до которой импорт файла уже выполнен,
+// чтобы прерванный импорт можно было продолжить.
+type ImportCheckpointRepository interface {
+	// GetCheckpoint возвращает номер последней обработанной строки или 0, если импорт не начинался.
+	GetCheckpoint(ctx context.Context, importID string) (int, error)
+	SaveCheckpointWithTx(ctx context.Context, tx pgx.Tx, importID string, line int) error
+	DeleteCheckpoint(ctx context.Context, importID string) error
+}
+
+type PostgresImportCheckpointRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresImportCheckpointRepository(pool *pgxpool.Pool) *PostgresImportCheckpointRepository {
+	return &PostgresImportCheckpointRepository{pool: pool}
+}
+
+func (r *PostgresImportCheckpointRepository) GetCheckpoint(ctx context.Context, importID string) (int, error) {
+	const q = `SELECT line FROM import_checkpoints WHERE import_id = $1`
+	var line int
+	err := r.pool.QueryRow(ctx, q, importID).Scan(&line)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return line, err
+}
+
+func (r *PostgresImportCheckpointRepository) SaveCheckpointWithTx(ctx context.Context, tx pgx.Tx, importID string, line int) error {
+	const q = `INSERT INTO import_checkpoints (import_id, line) VALUES ($1, $2)
+               ON CONFLICT (import_id) DO UPDATE SET line = EXCLUDED.line, updated_at = now()`
+	_, err := tx.Exec(ctx, q, importID, line)
+	return err
+}
+
+func (r *PostgresImportCheckpointRepository) DeleteCheckpoint(ctx context.Context, importID string) error {
+	const q = `DELETE FROM import_checkpoints WHERE import_id = $1`
+	_, err := r.pool.Exec(ctx, q, importID)
+	return err
+}
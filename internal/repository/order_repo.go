@@ -3,6 +3,10 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -12,12 +16,29 @@ import (
 
 type OrderRepository interface {
 	Save(ctx context.Context, msg domain.Order) error
-	ListUIDs(ctx context.Context, limit, offset int) ([]string, error)
-	List(ctx context.Context, limit, offset int) ([]domain.Order, error)
+	ListUIDs(ctx context.Context, filter domain.OrderFilter, limit, offset int) ([]string, error)
+	List(ctx context.Context, filter domain.OrderFilter, limit, offset int) ([]domain.Order, error)
 	Get(ctx context.Context, orderUID string) (domain.Order, error)
 	SaveWithTx(ctx context.Context, tx pgx.Tx, msg domain.Order) error
+	SaveBatchWithTx(ctx context.Context, tx pgx.Tx, orders []domain.Order) error
+	// Stream вызывает fn для каждого заказа, подходящего под фильтр, читая их из курсора
+	// порциями, без загрузки всей выборки в память.
+	Stream(ctx context.Context, filter domain.OrderFilter, fn func(domain.Order) error) error
+	// Stats считает количество заказов и выручку по интервалам date_created.
+	Stats(ctx context.Context, q domain.StatsQuery) ([]domain.StatsBucket, error)
+	// Search выполняет полнотекстовый поиск по имени получателя, адресу, названиям и брендам товаров.
+	Search(ctx context.Context, query string, limit, offset int) (domain.SearchPage, error)
+	// CustomerSummary возвращает сводку по заказам покупателя с topBrands самыми частыми брендами.
+	CustomerSummary(ctx context.Context, customerID string, topBrands int) (domain.CustomerSummary, error)
+	// FindByTrackNumber возвращает заказы с трек-номером заказа или одного из товаров.
+	FindByTrackNumber(ctx context.Context, trackNumber string, limit int) ([]domain.Order, error)
+	// FindItems возвращает товары, подходящие под запрос, начиная с последних сохранённых заказов.
+	FindItems(ctx context.Context, q domain.ItemQuery, limit, offset int) ([]domain.ItemMatch, error)
 }
 
+// Количество строк, читаемых из курсора за один FETCH
+const streamFetchSize = 500
+
 type PostgresOrderRepository struct {
 	pool *pgxpool.Pool
 }
@@ -26,14 +47,24 @@ func NewPostgresOrderRepository(pool *pgxpool.Pool) *PostgresOrderRepository {
 	return &PostgresOrderRepository{pool: pool}
 }
 
+// upsertOrderSQL сохраняет заказ, перезаписывая payload и время изменения существующего.
+const upsertOrderSQL = `INSERT INTO orders (order_uid, payload, updated_at) VALUES ($1, $2::jsonb, $3)
+               ON CONFLICT (order_uid) DO UPDATE SET payload = EXCLUDED.payload, updated_at = EXCLUDED.updated_at`
+
+// updatedAt возвращает время изменения заказа: заданное сервисом или текущее.
+func updatedAt(msg domain.Order) time.Time {
+	if msg.UpdatedAt.IsZero() {
+		return time.Now().UTC()
+	}
+	return msg.UpdatedAt
+}
+
 func (r *PostgresOrderRepository) Save(ctx context.Context, msg domain.Order) error {
 	payload, err := json.Marshal(msg)
 	if err != nil {
 		return err
 	}
-	const q = `INSERT INTO orders (order_uid, payload) VALUES ($1, $2::jsonb)
-               ON CONFLICT (order_uid) DO UPDATE SET payload = EXCLUDED.payload`
-	_, err = r.pool.Exec(ctx, q, msg.OrderUID, string(payload))
+	_, err = r.pool.Exec(ctx, upsertOrderSQL, msg.OrderUID, string(payload), updatedAt(msg))
 	return err
 }
 
@@ -42,15 +73,28 @@ func (r *PostgresOrderRepository) SaveWithTx(ctx context.Context, tx pgx.Tx, msg
 	if err != nil {
 		return err
 	}
-	const q = `INSERT INTO orders (order_uid, payload) VALUES ($1, $2::jsonb)
-               ON CONFLICT (order_uid) DO UPDATE SET payload = EXCLUDED.payload`
-	_, err = tx.Exec(ctx, q, msg.OrderUID, string(payload))
+	_, err = tx.Exec(ctx, upsertOrderSQL, msg.OrderUID, string(payload), updatedAt(msg))
 	return err
 }
 
-func (r *PostgresOrderRepository) ListUIDs(ctx context.Context, limit, offset int) ([]string, error) {
-	const q = `SELECT order_uid FROM orders ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	rows, err := r.pool.Query(ctx, q, limit, offset)
+// SaveBatchWithTx сохраняет несколько заказов за один обмен с БД.
+func (r *PostgresOrderRepository) SaveBatchWithTx(ctx context.Context, tx pgx.Tx, orders []domain.Order) error {
+	batch := &pgx.Batch{}
+	for _, order := range orders {
+		payload, err := json.Marshal(order)
+		if err != nil {
+			return err
+		}
+		batch.Queue(upsertOrderSQL, order.OrderUID, string(payload), updatedAt(order))
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+func (r *PostgresOrderRepository) ListUIDs(ctx context.Context, filter domain.OrderFilter, limit, offset int) ([]string, error) {
+	where, args := filterSQL(filter)
+	q := fmt.Sprintf(`SELECT order_uid FROM orders%s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		where, len(args)+1, len(args)+2)
+	rows, err := r.pool.Query(ctx, q, append(args, limit, offset)...)
 	if err != nil {
 		return nil, err
 	}
@@ -70,9 +114,11 @@ func (r *PostgresOrderRepository) ListUIDs(ctx context.Context, limit, offset in
 	return orders, nil
 }
 
-func (r *PostgresOrderRepository) List(ctx context.Context, limit, offset int) ([]domain.Order, error) {
-	const q = `SELECT payload FROM orders ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	rows, err := r.pool.Query(ctx, q, limit, offset)
+func (r *PostgresOrderRepository) List(ctx context.Context, filter domain.OrderFilter, limit, offset int) ([]domain.Order, error) {
+	where, args := filterSQL(filter)
+	q := fmt.Sprintf(`SELECT payload, updated_at FROM orders%s ORDER BY created_at DESC LIMIT $%d OFFSET $%d`,
+		where, len(args)+1, len(args)+2)
+	rows, err := r.pool.Query(ctx, q, append(args, limit, offset)...)
 	if err != nil {
 		return nil, err
 	}
@@ -80,12 +126,8 @@ func (r *PostgresOrderRepository) List(ctx context.Context, limit, offset int) (
 
 	orders := make([]domain.Order, 0, limit)
 	for rows.Next() {
-		var raw []byte
-		if err := rows.Scan(&raw); err != nil {
-			return nil, err
-		}
-		var ord domain.Order
-		if err := json.Unmarshal(raw, &ord); err != nil {
+		ord, err := scanOrder(rows)
+		if err != nil {
 			return nil, err
 		}
 		orders = append(orders, ord)
@@ -97,15 +139,310 @@ func (r *PostgresOrderRepository) List(ctx context.Context, limit, offset int) (
 }
 
 func (r *PostgresOrderRepository) Get(ctx context.Context, orderUID string) (domain.Order, error) {
-	const q = `SELECT payload FROM orders WHERE order_uid = $1`
+	const q = `SELECT payload, updated_at FROM orders WHERE order_uid = $1`
+	order, err := scanOrder(r.pool.QueryRow(ctx, q, orderUID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Order{}, fmt.Errorf("order %s: %w", orderUID, domain.ErrNotFound)
+	}
+	return order, err
+}
+
+// scanOrder читает заказ из строки с колонками payload и updated_at.
+func scanOrder(row pgx.Row) (domain.Order, error) {
 	var raw []byte
-	err := r.pool.QueryRow(ctx, q, orderUID).Scan(&raw)
-	if err != nil {
+	var updated time.Time
+	if err := row.Scan(&raw, &updated); err != nil {
 		return domain.Order{}, err
 	}
+	return decodeOrder(raw, updated)
+}
+
+// decodeOrder разбирает payload заказа и заполняет время изменения.
+func decodeOrder(raw []byte, updated time.Time) (domain.Order, error) {
 	var ord domain.Order
 	if err := json.Unmarshal(raw, &ord); err != nil {
 		return domain.Order{}, err
 	}
+	ord.UpdatedAt = updated.UTC()
 	return ord, nil
 }
+
+func (r *PostgresOrderRepository) Stream(ctx context.Context, filter domain.OrderFilter, fn func(domain.Order) error) error {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	where, args := filterSQL(filter)
+	q := fmt.Sprintf(`DECLARE orders_stream NO SCROLL CURSOR FOR
+               SELECT payload, updated_at FROM orders%s ORDER BY created_at DESC`, where)
+	if _, err := tx.Exec(ctx, q, args...); err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf(`FETCH FORWARD %d FROM orders_stream`, streamFetchSize)
+	for {
+		rows, err := tx.Query(ctx, fetch)
+		if err != nil {
+			return err
+		}
+		n := 0
+		for rows.Next() {
+			n++
+			ord, err := scanOrder(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			if err := fn(ord); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if rows.Err() != nil {
+			return rows.Err()
+		}
+		if n < streamFetchSize {
+			return nil
+		}
+	}
+}
+
+// statsGroupSQL — выражения для разбивки статистики. Бренд берётся из товаров,
+// которые разворачиваются в строки в запросе Stats.
+var statsGroupSQL = map[domain.StatsGroup]string{
+	domain.StatsByDeliveryService: `payload->>'delivery_service'`,
+	domain.StatsByProvider:        `payload->'payment'->>'provider'`,
+	domain.StatsByBank:            `payload->'payment'->>'bank'`,
+	domain.StatsByCurrency:        `payload->'payment'->>'currency'`,
+	domain.StatsByRegion:          `payload->'delivery'->>'region'`,
+	domain.StatsByBrand:           `item->>'brand'`,
+}
+
+func (r *PostgresOrderRepository) Stats(ctx context.Context, q domain.StatsQuery) ([]domain.StatsBucket, error) {
+	group := `''`
+	if q.GroupBy != "" {
+		expr, ok := statsGroupSQL[q.GroupBy]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown stats group %q", domain.ErrInvalidArgument, q.GroupBy)
+		}
+		group = "coalesce(" + expr + ", '')"
+	}
+	from, orders, revenue := "orders", "count(*)", `sum((payload->'payment'->>'amount')::bigint)`
+	if q.GroupBy == domain.StatsByBrand {
+		from = "orders CROSS JOIN LATERAL jsonb_array_elements(payload->'items') AS item"
+		orders, revenue = "count(DISTINCT order_uid)", `sum((item->>'total_price')::bigint)`
+	}
+
+	// Выручка считается отдельно по каждой валюте, как в CustomerSummary. У заказа одна валюта,
+	// поэтому количество заказов интервала — сумма количеств по валютам.
+	where, args := filterSQL(q.Filter)
+	args = append(args, string(q.Interval))
+	sql := fmt.Sprintf(`SELECT date_trunc($%d, (payload->>'date_created')::timestamptz AT TIME ZONE 'UTC') AS bucket,
+               %s AS grp, coalesce(payload->'payment'->>'currency', '') AS currency, %s, coalesce(%s, 0)::bigint
+               FROM %s%s GROUP BY 1, 2, 3 ORDER BY 1, 2, 3`, len(args), group, orders, revenue, from, where)
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []domain.StatsBucket{}
+	for rows.Next() {
+		var (
+			start           time.Time
+			grp, currency   string
+			count, subtotal int64
+		)
+		if err := rows.Scan(&start, &grp, &currency, &count, &subtotal); err != nil {
+			return nil, err
+		}
+		start = start.UTC()
+		if n := len(buckets); n == 0 || !buckets[n-1].Start.Equal(start) || buckets[n-1].Group != grp {
+			buckets = append(buckets, domain.StatsBucket{Start: start, Group: grp, Revenue: map[string]int64{}})
+		}
+		b := &buckets[len(buckets)-1]
+		b.Orders += count
+		b.Revenue[currency] = subtotal
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return buckets, nil
+}
+
+func (r *PostgresOrderRepository) CustomerSummary(ctx context.Context, customerID string, topBrands int) (domain.CustomerSummary, error) {
+	// Запросы сводки читают один снимок данных
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return domain.CustomerSummary{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	sum := domain.CustomerSummary{CustomerID: customerID, TotalSpent: map[string]int64{}, TopBrands: []domain.BrandCount{}}
+	var first, last *time.Time
+	err = tx.QueryRow(ctx, `SELECT count(*), min((payload->>'date_created')::timestamptz), max((payload->>'date_created')::timestamptz)
+               FROM orders WHERE payload->>'customer_id' = $1`, customerID).Scan(&sum.Orders, &first, &last)
+	if err != nil {
+		return domain.CustomerSummary{}, err
+	}
+	if sum.Orders == 0 {
+		return domain.CustomerSummary{}, fmt.Errorf("customer %s: %w", customerID, domain.ErrNotFound)
+	}
+	sum.FirstOrderAt, sum.LastOrderAt = first.UTC(), last.UTC()
+
+	rows, err := tx.Query(ctx, `SELECT coalesce(payload->'payment'->>'currency', ''), coalesce(sum((payload->'payment'->>'amount')::bigint), 0)::bigint
+               FROM orders WHERE payload->>'customer_id' = $1 GROUP BY 1`, customerID)
+	if err != nil {
+		return domain.CustomerSummary{}, err
+	}
+	for rows.Next() {
+		var currency string
+		var amount int64
+		if err := rows.Scan(&currency, &amount); err != nil {
+			rows.Close()
+			return domain.CustomerSummary{}, err
+		}
+		sum.TotalSpent[currency] = amount
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return domain.CustomerSummary{}, rows.Err()
+	}
+
+	rows, err = tx.Query(ctx, `SELECT item->>'brand', count(DISTINCT order_uid), count(*)
+               FROM orders CROSS JOIN LATERAL jsonb_array_elements(payload->'items') AS item
+               WHERE payload->>'customer_id' = $1 AND coalesce(item->>'brand', '') <> ''
+               GROUP BY 1 ORDER BY 3 DESC, 1 LIMIT $2`, customerID, topBrands)
+	if err != nil {
+		return domain.CustomerSummary{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var b domain.BrandCount
+		if err := rows.Scan(&b.Brand, &b.Orders, &b.Items); err != nil {
+			return domain.CustomerSummary{}, err
+		}
+		sum.TopBrands = append(sum.TopBrands, b)
+	}
+	if rows.Err() != nil {
+		return domain.CustomerSummary{}, rows.Err()
+	}
+	return sum, nil
+}
+
+func (r *PostgresOrderRepository) FindByTrackNumber(ctx context.Context, trackNumber string, limit int) ([]domain.Order, error) {
+	// Условие по товарам записано через @>, чтобы использовать GIN индекс idx_orders_items
+	items, err := json.Marshal([]map[string]string{{"track_number": trackNumber}})
+	if err != nil {
+		return nil, err
+	}
+	const q = `SELECT payload, updated_at FROM orders
+               WHERE payload->>'track_number' = $1 OR payload->'items' @> $2::jsonb
+               ORDER BY created_at DESC LIMIT $3`
+	rows, err := r.pool.Query(ctx, q, trackNumber, string(items), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []domain.Order{}
+	for rows.Next() {
+		ord, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, ord)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return orders, nil
+}
+
+// itemConditions возвращает JSON объект с заданными полями запроса товаров.
+func itemConditions(q domain.ItemQuery) map[string]any {
+	cond := map[string]any{}
+	if q.Rid != "" {
+		cond["rid"] = q.Rid
+	}
+	if q.NmID != 0 {
+		cond["nm_id"] = q.NmID
+	}
+	if q.ChrtID != 0 {
+		cond["chrt_id"] = q.ChrtID
+	}
+	return cond
+}
+
+func (r *PostgresOrderRepository) FindItems(ctx context.Context, q domain.ItemQuery, limit, offset int) ([]domain.ItemMatch, error) {
+	cond := itemConditions(q)
+	item, err := json.Marshal(cond)
+	if err != nil {
+		return nil, err
+	}
+	items, err := json.Marshal([]map[string]any{cond})
+	if err != nil {
+		return nil, err
+	}
+	// Условие по массиву отбирает заказы по GIN индексу, условие по элементу — сами товары
+	const sql = `SELECT order_uid, coalesce(payload->>'track_number', ''), coalesce(payload->>'customer_id', ''),
+               coalesce((payload->>'date_created')::timestamptz, 'epoch'), item
+               FROM orders CROSS JOIN LATERAL jsonb_array_elements(payload->'items') AS item
+               WHERE payload->'items' @> $1::jsonb AND item @> $2::jsonb
+               ORDER BY created_at DESC, order_uid LIMIT $3 OFFSET $4`
+	rows, err := r.pool.Query(ctx, sql, string(items), string(item), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []domain.ItemMatch{}
+	for rows.Next() {
+		var m domain.ItemMatch
+		var raw []byte
+		if err := rows.Scan(&m.OrderUID, &m.TrackNumber, &m.CustomerID, &m.DateCreated, &raw); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &m.Item); err != nil {
+			return nil, err
+		}
+		m.DateCreated = m.DateCreated.UTC()
+		matches = append(matches, m)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return matches, nil
+}
+
+// filterSQL строит условие WHERE для фильтра заказов и его аргументы.
+// Если фильтр пуст, возвращает пустую строку.
+func filterSQL(f domain.OrderFilter) (string, []any) {
+	var conds []string
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.CustomerID != "" {
+		add(`payload->>'customer_id' = $%d`, f.CustomerID)
+	}
+	if f.DeliveryService != "" {
+		add(`payload->>'delivery_service' = $%d`, f.DeliveryService)
+	}
+	if !f.From.IsZero() {
+		add(`(payload->>'date_created')::timestamptz >= $%d`, f.From)
+	}
+	if !f.To.IsZero() {
+		add(`(payload->>'date_created')::timestamptz < $%d`, f.To)
+	}
+
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
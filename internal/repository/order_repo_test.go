@@ -59,13 +59,13 @@ func (suite *OrderRepositoryTestSuite) SetupTest() {
 	suite.repo = repository.NewPostgresOrderRepository(suite.pool)
 
 	// Очищаем таблицу перед каждым тестом
-	_, err := suite.pool.Exec(suite.ctx, "TRUNCATE TABLE orders RESTART IDENTITY CASCADE")
+	_, err := suite.pool.Exec(suite.ctx, "TRUNCATE TABLE orders, outbox RESTART IDENTITY CASCADE")
 	require.NoError(suite.T(), err)
 }
 
 func (suite *OrderRepositoryTestSuite) TearDownTest() {
 	// Очищаем таблицу после каждого теста
-	_, err := suite.pool.Exec(suite.ctx, "TRUNCATE TABLE orders RESTART IDENTITY CASCADE")
+	_, err := suite.pool.Exec(suite.ctx, "TRUNCATE TABLE orders, outbox RESTART IDENTITY CASCADE")
 	require.NoError(suite.T(), err)
 }
 
@@ -145,10 +145,36 @@ func (suite *OrderRepositoryTestSuite) TestGetOrder() {
 	assert.Equal(suite.T(), len(order.Items), len(retrievedOrder.Items))
 }
 
+func (suite *OrderRepositoryTestSuite) TestGetOrderWithHeaders() {
+	order := createTestOrder("test-order-1")
+	order.Headers = map[string]string{domain.HeaderCorrelationID: "corr-1", domain.HeaderSourceSystem: "crm"}
+	require.NoError(suite.T(), suite.repo.Save(suite.ctx, order))
+
+	retrievedOrder, err := suite.repo.Get(suite.ctx, order.OrderUID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), order.Headers, retrievedOrder.Headers)
+}
+
+func (suite *OrderRepositoryTestSuite) TestGetOrderUpdatedAt() {
+	order := createTestOrder("test-order-1")
+	require.NoError(suite.T(), suite.repo.Save(suite.ctx, order))
+
+	retrievedOrder, err := suite.repo.Get(suite.ctx, order.OrderUID)
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), retrievedOrder.UpdatedAt.IsZero())
+
+	// Перезапись заказа обновляет время изменения
+	order.UpdatedAt = retrievedOrder.UpdatedAt.Add(time.Hour)
+	require.NoError(suite.T(), suite.repo.Save(suite.ctx, order))
+	retrievedOrder, err = suite.repo.Get(suite.ctx, order.OrderUID)
+	require.NoError(suite.T(), err)
+	assert.True(suite.T(), order.UpdatedAt.Equal(retrievedOrder.UpdatedAt))
+}
+
 func (suite *OrderRepositoryTestSuite) TestGetOrderNotFound() {
 	// Пытаемся получить несуществующий заказ
 	_, err := suite.repo.Get(suite.ctx, "non-existent-order")
-	assert.Error(suite.T(), err)
+	assert.ErrorIs(suite.T(), err, domain.ErrNotFound)
 }
 
 func (suite *OrderRepositoryTestSuite) TestListOrders() {
@@ -168,24 +194,24 @@ func (suite *OrderRepositoryTestSuite) TestListOrders() {
 	}
 
 	// Получаем список заказов с лимитом 3
-	orderUIDs, err := suite.repo.ListUIDs(suite.ctx, 3, 0)
+	orderUIDs, err := suite.repo.ListUIDs(suite.ctx, domain.OrderFilter{}, 3, 0)
 	require.NoError(suite.T(), err)
 	assert.Len(suite.T(), orderUIDs, 3)
 
 	// Получаем список заказов с лимитом 2 и смещением 1
-	orderUIDs, err = suite.repo.ListUIDs(suite.ctx, 2, 1)
+	orderUIDs, err = suite.repo.ListUIDs(suite.ctx, domain.OrderFilter{}, 2, 1)
 	require.NoError(suite.T(), err)
 	assert.Len(suite.T(), orderUIDs, 2)
 
 	// Получаем все заказы
-	orderUIDs, err = suite.repo.ListUIDs(suite.ctx, 10, 0)
+	orderUIDs, err = suite.repo.ListUIDs(suite.ctx, domain.OrderFilter{}, 10, 0)
 	require.NoError(suite.T(), err)
 	assert.Len(suite.T(), orderUIDs, 5)
 }
 
 func (suite *OrderRepositoryTestSuite) TestListOrdersEmpty() {
 	// Получаем список заказов из пустой таблицы
-	orderUIDs, err := suite.repo.ListUIDs(suite.ctx, 10, 0)
+	orderUIDs, err := suite.repo.ListUIDs(suite.ctx, domain.OrderFilter{}, 10, 0)
 	require.NoError(suite.T(), err)
 	assert.Empty(suite.T(), orderUIDs)
 }
@@ -205,19 +231,353 @@ func (suite *OrderRepositoryTestSuite) TestListOrdersWithOffset() {
 	}
 
 	// Получаем заказы с разными смещениями
-	orderUIDs, err := suite.repo.ListUIDs(suite.ctx, 2, 0)
+	orderUIDs, err := suite.repo.ListUIDs(suite.ctx, domain.OrderFilter{}, 2, 0)
 	require.NoError(suite.T(), err)
 	assert.Len(suite.T(), orderUIDs, 2)
 
-	orderUIDs, err = suite.repo.ListUIDs(suite.ctx, 2, 2)
+	orderUIDs, err = suite.repo.ListUIDs(suite.ctx, domain.OrderFilter{}, 2, 2)
 	require.NoError(suite.T(), err)
 	assert.Len(suite.T(), orderUIDs, 1)
 
-	orderUIDs, err = suite.repo.ListUIDs(suite.ctx, 2, 5)
+	orderUIDs, err = suite.repo.ListUIDs(suite.ctx, domain.OrderFilter{}, 2, 5)
 	require.NoError(suite.T(), err)
 	assert.Empty(suite.T(), orderUIDs)
 }
 
+func (suite *OrderRepositoryTestSuite) TestListOrdersWithFilter() {
+	// Создаем заказы разных покупателей и служб доставки
+	order1 := createTestOrder("order-1")
+	order2 := createTestOrder("order-2")
+	order2.CustomerID = "another"
+	order3 := createTestOrder("order-3")
+	order3.DeliveryService = "dhl"
+	order3.DateCreated = time.Now().Add(-48 * time.Hour)
+
+	for _, order := range []domain.Order{order1, order2, order3} {
+		err := suite.repo.Save(suite.ctx, order)
+		require.NoError(suite.T(), err)
+	}
+
+	orderUIDs, err := suite.repo.ListUIDs(suite.ctx, domain.OrderFilter{CustomerID: "another"}, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"order-2"}, orderUIDs)
+
+	orderUIDs, err = suite.repo.ListUIDs(suite.ctx, domain.OrderFilter{DeliveryService: "dhl"}, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"order-3"}, orderUIDs)
+
+	// Фильтр по date_created отсекает заказ, созданный двое суток назад
+	orderUIDs, err = suite.repo.ListUIDs(suite.ctx, domain.OrderFilter{From: time.Now().Add(-24 * time.Hour)}, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), orderUIDs, 2)
+	assert.NotContains(suite.T(), orderUIDs, "order-3")
+
+	orders, err := suite.repo.List(suite.ctx, domain.OrderFilter{DeliveryService: "dhl"}, 10, 0)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), orders, 1)
+	assert.Equal(suite.T(), "order-3", orders[0].OrderUID)
+}
+
+func (suite *OrderRepositoryTestSuite) TestStats() {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	order1 := createTestOrder("order-1")
+	order1.DateCreated = day.Add(9 * time.Hour)
+	order2 := createTestOrder("order-2")
+	order2.DateCreated = day.Add(15 * time.Hour)
+	order2.Payment.Bank = "sber"
+	order2.Items = append(order2.Items, domain.Items{Brand: "Nivea", TotalPrice: 100})
+	order3 := createTestOrder("order-3")
+	order3.DateCreated = day.Add(30 * time.Hour)
+
+	for _, order := range []domain.Order{order1, order2, order3} {
+		require.NoError(suite.T(), suite.repo.Save(suite.ctx, order))
+	}
+	period := domain.OrderFilter{From: day, To: day.AddDate(0, 0, 7)}
+
+	buckets, err := suite.repo.Stats(suite.ctx, domain.StatsQuery{Filter: period, Interval: domain.StatsDay})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.StatsBucket{
+		{Start: day, Orders: 2, Revenue: map[string]int64{"USD": 2 * 1817}},
+		{Start: day.AddDate(0, 0, 1), Orders: 1, Revenue: map[string]int64{"USD": 1817}},
+	}, buckets)
+
+	buckets, err = suite.repo.Stats(suite.ctx, domain.StatsQuery{Filter: period, Interval: domain.StatsDay, GroupBy: domain.StatsByBank})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.StatsBucket{
+		{Start: day, Group: "alpha", Orders: 1, Revenue: map[string]int64{"USD": 1817}},
+		{Start: day, Group: "sber", Orders: 1, Revenue: map[string]int64{"USD": 1817}},
+		{Start: day.AddDate(0, 0, 1), Group: "alpha", Orders: 1, Revenue: map[string]int64{"USD": 1817}},
+	}, buckets)
+
+	// 1 мая 2024 — среда, неделя начинается с понедельника 29 апреля
+	buckets, err = suite.repo.Stats(suite.ctx, domain.StatsQuery{Filter: period, Interval: domain.StatsWeek, GroupBy: domain.StatsByBrand})
+	require.NoError(suite.T(), err)
+	week := day.AddDate(0, 0, -2)
+	assert.Equal(suite.T(), []domain.StatsBucket{
+		{Start: week, Group: "Nivea", Orders: 1, Revenue: map[string]int64{"USD": 100}},
+		{Start: week, Group: "Vivienne Sabo", Orders: 3, Revenue: map[string]int64{"USD": 3 * 317}},
+	}, buckets)
+}
+
+func (suite *OrderRepositoryTestSuite) TestStatsMixedCurrencies() {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	order1 := createTestOrder("order-1")
+	order1.DateCreated = day.Add(9 * time.Hour)
+	order2 := createTestOrder("order-2")
+	order2.DateCreated = day.Add(10 * time.Hour)
+	order2.Payment.Currency = "RUB"
+	order2.Payment.Amount = 150000
+	order3 := createTestOrder("order-3")
+	order3.DateCreated = day.Add(11 * time.Hour)
+
+	for _, order := range []domain.Order{order1, order2, order3} {
+		require.NoError(suite.T(), suite.repo.Save(suite.ctx, order))
+	}
+	period := domain.OrderFilter{From: day, To: day.AddDate(0, 0, 1)}
+
+	// Суммы в разных валютах одного интервала не складываются
+	buckets, err := suite.repo.Stats(suite.ctx, domain.StatsQuery{Filter: period, Interval: domain.StatsDay})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.StatsBucket{
+		{Start: day, Orders: 3, Revenue: map[string]int64{"USD": 2 * 1817, "RUB": 150000}},
+	}, buckets)
+
+	buckets, err = suite.repo.Stats(suite.ctx, domain.StatsQuery{Filter: period, Interval: domain.StatsDay, GroupBy: domain.StatsByBrand})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.StatsBucket{
+		{Start: day, Group: "Vivienne Sabo", Orders: 3, Revenue: map[string]int64{"USD": 2 * 317, "RUB": 317}},
+	}, buckets)
+}
+
+func (suite *OrderRepositoryTestSuite) TestCustomerSummary() {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	order1 := createTestOrder("order-1")
+	order1.DateCreated = day
+	order2 := createTestOrder("order-2")
+	order2.DateCreated = day.AddDate(0, 0, 3)
+	order2.Payment.Currency = "RUB"
+	order2.Items = append(order2.Items, domain.Items{Brand: "Nivea"}, domain.Items{Brand: "Nivea"})
+	other := createTestOrder("order-3")
+	other.CustomerID = "another"
+
+	for _, order := range []domain.Order{order1, order2, other} {
+		require.NoError(suite.T(), suite.repo.Save(suite.ctx, order))
+	}
+
+	sum, err := suite.repo.CustomerSummary(suite.ctx, "test", 5)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.CustomerSummary{
+		CustomerID:   "test",
+		Orders:       2,
+		TotalSpent:   map[string]int64{"USD": 1817, "RUB": 1817},
+		FirstOrderAt: day,
+		LastOrderAt:  day.AddDate(0, 0, 3),
+		TopBrands: []domain.BrandCount{
+			{Brand: "Nivea", Orders: 1, Items: 2},
+			{Brand: "Vivienne Sabo", Orders: 2, Items: 2},
+		},
+	}, sum)
+
+	sum, err = suite.repo.CustomerSummary(suite.ctx, "test", 1)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), sum.TopBrands, 1)
+
+	_, err = suite.repo.CustomerSummary(suite.ctx, "nobody", 5)
+	assert.ErrorIs(suite.T(), err, domain.ErrNotFound)
+}
+
+func (suite *OrderRepositoryTestSuite) TestFindByTrackNumber() {
+	order1 := createTestOrder("order-1")
+	order1.TrackNumber = "TRACK-1"
+	order2 := createTestOrder("order-2")
+	order2.TrackNumber = "TRACK-2"
+	// Трек-номер отдельного товара отличается от трек-номера заказа
+	order2.Items[0].TrackNumber = "TRACK-1"
+	order3 := createTestOrder("order-3")
+	order3.TrackNumber = "TRACK-3"
+
+	for _, order := range []domain.Order{order1, order2, order3} {
+		require.NoError(suite.T(), suite.repo.Save(suite.ctx, order))
+	}
+
+	orders, err := suite.repo.FindByTrackNumber(suite.ctx, "TRACK-1", 10)
+	require.NoError(suite.T(), err)
+	var uids []string
+	for _, o := range orders {
+		uids = append(uids, o.OrderUID)
+	}
+	assert.ElementsMatch(suite.T(), []string{"order-1", "order-2"}, uids)
+
+	orders, err = suite.repo.FindByTrackNumber(suite.ctx, "UNKNOWN", 10)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), orders)
+}
+
+func (suite *OrderRepositoryTestSuite) TestFindItems() {
+	order1 := createTestOrder("order-1")
+	order1.Items = append(order1.Items, domain.Items{ChrtID: 1, NmID: 2389212, Rid: "rid-2", Brand: "Nivea"})
+	order2 := createTestOrder("order-2")
+	order2.Items[0].Rid = "rid-3"
+
+	for _, order := range []domain.Order{order1, order2} {
+		require.NoError(suite.T(), suite.repo.Save(suite.ctx, order))
+	}
+
+	// nm_id есть у обоих товаров первого заказа и у товара второго
+	matches, err := suite.repo.FindItems(suite.ctx, domain.ItemQuery{NmID: 2389212}, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), matches, 3)
+
+	// Условия должны выполняться для одного и того же товара
+	matches, err = suite.repo.FindItems(suite.ctx, domain.ItemQuery{NmID: 2389212, ChrtID: 1}, 10, 0)
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), matches, 1)
+	assert.Equal(suite.T(), "order-1", matches[0].OrderUID)
+	assert.Equal(suite.T(), "Nivea", matches[0].Item.Brand)
+
+	matches, err = suite.repo.FindItems(suite.ctx, domain.ItemQuery{Rid: "rid-3", ChrtID: 1}, 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), matches)
+}
+
+func (suite *OrderRepositoryTestSuite) TestSearch() {
+	order1 := createTestOrder("order-1")
+	order1.Delivery.Name = "Ivan Petrov"
+	order2 := createTestOrder("order-2")
+	order2.Delivery.Address = "Petrov street 5"
+	order3 := createTestOrder("order-3")
+	order3.Items[0].Name = "Lipstick"
+
+	for _, order := range []domain.Order{order1, order2, order3} {
+		require.NoError(suite.T(), suite.repo.Save(suite.ctx, order))
+	}
+
+	// Совпадение в имени получателя ранжируется выше совпадения в адресе
+	page, err := suite.repo.Search(suite.ctx, "petrov", 10, 0)
+	require.NoError(suite.T(), err)
+	assert.EqualValues(suite.T(), 2, page.Total)
+	require.Len(suite.T(), page.Results, 2)
+	assert.Equal(suite.T(), "order-1", page.Results[0].Order.OrderUID)
+	assert.Equal(suite.T(), "order-2", page.Results[1].Order.OrderUID)
+	assert.Greater(suite.T(), page.Results[0].Rank, page.Results[1].Rank)
+	assert.Contains(suite.T(), page.Results[0].Highlight, "<mark>Petrov</mark>")
+
+	page, err = suite.repo.Search(suite.ctx, "petrov", 1, 1)
+	require.NoError(suite.T(), err)
+	assert.EqualValues(suite.T(), 2, page.Total)
+	require.Len(suite.T(), page.Results, 1)
+	assert.Equal(suite.T(), "order-2", page.Results[0].Order.OrderUID)
+
+	// Вектор обновляется триггером при перезаписи заказа
+	order3.Items[0].Name = "Mascaras"
+	require.NoError(suite.T(), suite.repo.Save(suite.ctx, order3))
+	page, err = suite.repo.Search(suite.ctx, "lipstick", 10, 0)
+	require.NoError(suite.T(), err)
+	assert.Empty(suite.T(), page.Results)
+}
+
+func (suite *OrderRepositoryTestSuite) TestStreamOrders() {
+	// Создаем больше заказов, чем читается из курсора за один раз
+	for i := 0; i < 501; i++ {
+		err := suite.repo.Save(suite.ctx, createTestOrder(fmt.Sprintf("order-%d", i)))
+		require.NoError(suite.T(), err)
+	}
+
+	var streamed []string
+	err := suite.repo.Stream(suite.ctx, domain.OrderFilter{}, func(order domain.Order) error {
+		streamed = append(streamed, order.OrderUID)
+		return nil
+	})
+	require.NoError(suite.T(), err)
+	assert.Len(suite.T(), streamed, 501)
+
+	// Ошибка из обработчика прерывает выгрузку
+	stopErr := fmt.Errorf("stop")
+	err = suite.repo.Stream(suite.ctx, domain.OrderFilter{}, func(domain.Order) error { return stopErr })
+	assert.ErrorIs(suite.T(), err, stopErr)
+}
+
+func (suite *OrderRepositoryTestSuite) TestSaveBatchWithTx() {
+	// Сохраняем пачку заказов в одной транзакции
+	orders := []domain.Order{
+		createTestOrder("order-1"),
+		createTestOrder("order-2"),
+		createTestOrder("order-3"),
+	}
+
+	tx, err := suite.pool.Begin(suite.ctx)
+	require.NoError(suite.T(), err)
+	err = suite.repo.SaveBatchWithTx(suite.ctx, tx, orders)
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), tx.Commit(suite.ctx))
+
+	var count int
+	err = suite.pool.QueryRow(suite.ctx, "SELECT COUNT(*) FROM orders").Scan(&count)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, count)
+}
+
+func (suite *OrderRepositoryTestSuite) TestOutboxPublishPending() {
+	outbox := repository.NewPostgresOutboxRepository(suite.pool)
+
+	// Событие записывается в транзакции вместе с заказом
+	tx, err := suite.pool.Begin(suite.ctx)
+	require.NoError(suite.T(), err)
+	err = outbox.AddWithTx(suite.ctx, tx, repository.OutboxEvent{EventType: "order.stored", Key: "order-1", Payload: []byte(`{}`)})
+	require.NoError(suite.T(), err)
+	err = outbox.AddBatchWithTx(suite.ctx, tx, []repository.OutboxEvent{{EventType: "order.stored", Key: "order-2", Payload: []byte(`{}`)}})
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), tx.Commit(suite.ctx))
+
+	// Ошибка публикации откатывает транзакцию, события остаются неопубликованными
+	_, err = outbox.PublishPending(suite.ctx, 10, func([]repository.OutboxEvent) (repository.OutboxResult, error) {
+		return repository.OutboxResult{}, fmt.Errorf("broker unavailable")
+	})
+	require.Error(suite.T(), err)
+
+	var keys []string
+	n, err := outbox.PublishPending(suite.ctx, 10, func(events []repository.OutboxEvent) (repository.OutboxResult, error) {
+		for _, e := range events {
+			keys = append(keys, e.Key)
+		}
+		return repository.OutboxResult{Published: []int64{events[0].ID}}, nil
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, n)
+	assert.Equal(suite.T(), []string{"order-1", "order-2"}, keys)
+
+	// Повторно передаётся только неопубликованное событие
+	keys = nil
+	_, err = outbox.PublishPending(suite.ctx, 10, func(events []repository.OutboxEvent) (repository.OutboxResult, error) {
+		for _, e := range events {
+			keys = append(keys, e.Key)
+		}
+		return repository.OutboxResult{Retry: []repository.OutboxRetry{{ID: events[0].ID, Delay: time.Hour}}}, nil
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"order-2"}, keys)
+
+	// Отложенное событие не выбирается до наступления срока повторной попытки
+	var attempts int
+	err = suite.pool.QueryRow(suite.ctx, "SELECT attempts FROM outbox WHERE event_key = 'order-2'").Scan(&attempts)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, attempts)
+	keys = nil
+	n, err = outbox.PublishPending(suite.ctx, 10, func(events []repository.OutboxEvent) (repository.OutboxResult, error) {
+		for _, e := range events {
+			keys = append(keys, e.Key)
+		}
+		return repository.OutboxResult{}, nil
+	})
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, n)
+	assert.Empty(suite.T(), keys)
+
+	deleted, err := outbox.DeletePublishedBefore(suite.ctx, time.Now().Add(time.Minute))
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), deleted)
+}
+
 func (suite *OrderRepositoryTestSuite) TestSaveOrderInvalidJSON() {
 	// Создаем заказ с некорректными данными, которые могут вызвать ошибку JSON
 	order := createTestOrder("test-order-1")
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OutboxEvent — событие, записанное в outbox в одной транзакции с изменением данных
+// и ожидающее публикации в Kafka.
+type OutboxEvent struct {
+	ID        int64
+	EventType string
+	Key       string
+	Payload   []byte
+	CreatedAt time.Time
+	// Attempts — количество неудачных попыток публикации
+	Attempts int
+}
+
+// OutboxRetry откладывает повторную публикацию события на Delay.
+type OutboxRetry struct {
+	ID    int64
+	Delay time.Duration
+}
+
+// OutboxResult — итог публикации пачки событий: Published помечаются опубликованными,
+// Retry остаются в outbox и выбираются повторно не раньше, чем через заданную задержку.
+type OutboxResult struct {
+	Published []int64
+	Retry     []OutboxRetry
+}
+
+type OutboxRepository interface {
+	AddWithTx(ctx context.Context, tx pgx.Tx, event OutboxEvent) error
+	// AddBatchWithTx записывает несколько событий за один обмен с БД.
+	AddBatchWithTx(ctx context.Context, tx pgx.Tx, events []OutboxEvent) error
+	// PublishPending блокирует до limit неопубликованных событий, срок повторной попытки которых
+	// наступил (в порядке записи), передаёт их в publish, помечает опубликованными события из
+	// Published и откладывает события из Retry — всё в одной транзакции.
+	// Если publish вернул ошибку, отметки не сохраняются и события будут переданы повторно.
+	PublishPending(ctx context.Context, limit int, publish func([]OutboxEvent) (OutboxResult, error)) (int, error)
+	// DeletePublishedBefore удаляет события, опубликованные раньше t.
+	DeletePublishedBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+type PostgresOutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresOutboxRepository(pool *pgxpool.Pool) *PostgresOutboxRepository {
+	return &PostgresOutboxRepository{pool: pool}
+}
+
+const insertOutboxSQL = `INSERT INTO outbox (event_type, event_key, payload) VALUES ($1, $2, $3::jsonb)`
+
+func (r *PostgresOutboxRepository) AddWithTx(ctx context.Context, tx pgx.Tx, event OutboxEvent) error {
+	_, err := tx.Exec(ctx, insertOutboxSQL, event.EventType, event.Key, string(event.Payload))
+	return err
+}
+
+func (r *PostgresOutboxRepository) AddBatchWithTx(ctx context.Context, tx pgx.Tx, events []OutboxEvent) error {
+	batch := &pgx.Batch{}
+	for _, event := range events {
+		batch.Queue(insertOutboxSQL, event.EventType, event.Key, string(event.Payload))
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+func (r *PostgresOutboxRepository) PublishPending(ctx context.Context, limit int, publish func([]OutboxEvent) (OutboxResult, error)) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// SKIP LOCKED позволяет нескольким экземплярам приложения разбирать outbox параллельно
+	const q = `SELECT id, event_type, event_key, payload, created_at, attempts FROM outbox
+               WHERE published_at IS NULL AND next_attempt_at <= now()
+               ORDER BY id LIMIT $1 FOR UPDATE SKIP LOCKED`
+	rows, err := tx.Query(ctx, q, limit)
+	if err != nil {
+		return 0, err
+	}
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (OutboxEvent, error) {
+		var e OutboxEvent
+		err := row.Scan(&e.ID, &e.EventType, &e.Key, &e.Payload, &e.CreatedAt, &e.Attempts)
+		return e, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	res, err := publish(events)
+	if err != nil {
+		return 0, err
+	}
+	if len(res.Published) > 0 {
+		const upd = `UPDATE outbox SET published_at = now() WHERE id = ANY($1)`
+		if _, err := tx.Exec(ctx, upd, res.Published); err != nil {
+			return 0, err
+		}
+	}
+	if len(res.Retry) > 0 {
+		ids := make([]int64, 0, len(res.Retry))
+		delays := make([]float64, 0, len(res.Retry))
+		for _, r := range res.Retry {
+			ids = append(ids, r.ID)
+			delays = append(delays, r.Delay.Seconds())
+		}
+		const retry = `UPDATE outbox SET attempts = attempts + 1, next_attempt_at = now() + make_interval(secs => r.delay)
+                       FROM unnest($1::bigint[], $2::double precision[]) AS r(id, delay)
+                       WHERE outbox.id = r.id`
+		if _, err := tx.Exec(ctx, retry, ids, delays); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(res.Published), nil
+}
+
+func (r *PostgresOutboxRepository) DeletePublishedBefore(ctx context.Context, t time.Time) (int64, error) {
+	const q = `DELETE FROM outbox WHERE published_at IS NOT NULL AND published_at < $1`
+	tag, err := r.pool.Exec(ctx, q, t)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
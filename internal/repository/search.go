@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"strings"
+	"time"
+
+	"wb-l0-go/internal/domain"
+)
+
+// Маркеры найденных слов во фрагментах ts_headline. Фрагменты экранируются как HTML
+// уже после выделения, поэтому маркеры не могут встретиться в тексте заказа как разметка.
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+)
+
+// Параметры ts_headline: до трёх фрагментов текста заказа вокруг найденных слов
+const headlineOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxFragments=3, MaxWords=15, MinWords=5, FragmentDelimiter=" … "`
+
+// Search ищет заказы по словам запроса в формате websearch_to_tsquery (кавычки для фраз, "-" для исключения,
+// "or" для альтернатив) и возвращает их в порядке убывания релевантности.
+func (r *PostgresOrderRepository) Search(ctx context.Context, query string, limit, offset int) (domain.SearchPage, error) {
+	page := domain.SearchPage{Query: query, Results: []domain.SearchResult{}}
+	const countSQL = `SELECT count(*) FROM orders WHERE search_vector @@ websearch_to_tsquery('simple', $1)`
+	if err := r.pool.QueryRow(ctx, countSQL, query).Scan(&page.Total); err != nil {
+		return page, err
+	}
+	if page.Total == 0 {
+		return page, nil
+	}
+
+	const searchSQL = `SELECT payload, updated_at, ts_rank(search_vector, q),
+               ts_headline('simple', orders_search_document(payload), q, $2)
+               FROM orders, websearch_to_tsquery('simple', $1) AS q
+               WHERE search_vector @@ q
+               ORDER BY 3 DESC, created_at DESC, order_uid LIMIT $3 OFFSET $4`
+	rows, err := r.pool.Query(ctx, searchSQL, query, headlineOptions, limit, offset)
+	if err != nil {
+		return page, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var res domain.SearchResult
+		var raw []byte
+		var updated time.Time
+		var headline string
+		if err := rows.Scan(&raw, &updated, &res.Rank, &headline); err != nil {
+			return page, err
+		}
+		if res.Order, err = decodeOrder(raw, updated); err != nil {
+			return page, err
+		}
+		res.Highlight = highlightHTML(headline)
+		page.Results = append(page.Results, res)
+	}
+	return page, rows.Err()
+}
+
+// highlightHTML экранирует фрагмент ts_headline и заменяет маркеры найденных слов на <mark>.
+func highlightHTML(headline string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(headline))
+}
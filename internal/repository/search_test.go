@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlightHTML(t *testing.T) {
+	headline := "Lipstick \x02<red>\x03 & Mascaras"
+	assert.Equal(t, "Lipstick <mark>&lt;red&gt;</mark> &amp; Mascaras", highlightHTML(headline))
+}
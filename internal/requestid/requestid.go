@@ -0,0 +1,48 @@
+// Package requestid передаёт идентификатор запроса через context.Context.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header — HTTP заголовок с идентификатором запроса
+const Header = "X-Request-ID"
+
+// Максимальная длина идентификатора, принимаемого от клиента
+const maxLen = 128
+
+type ctxKey struct{}
+
+// New генерирует новый идентификатор запроса.
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Valid сообщает, можно ли использовать идентификатор, полученный от клиента:
+// непустой, не длиннее 128 символов, только печатные ASCII символы.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// WithContext возвращает контекст с идентификатором запроса.
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext возвращает идентификатор запроса или пустую строку, если он не задан.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/importer"
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/repository"
+)
+
+// ImportRejection описывает отклонённую при импорте запись.
+type ImportRejection struct {
+	Line     int    `json:"line"`
+	OrderUID string `json:"order_uid,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// ImportResult — итог импорта файла.
+type ImportResult struct {
+	ImportID string `json:"import_id"`
+	// ResumedFrom — строка, после которой продолжен прерванный ранее импорт (0 — импорт с начала)
+	ResumedFrom int `json:"resumed_from"`
+	// LastLine — последняя строка файла, изменения по которой сохранены в БД
+	LastLine int `json:"last_line"`
+	Imported int `json:"imported"`
+	Rejected int `json:"rejected"`
+	// Skipped — записи, пропущенные как уже импортированные при продолжении
+	Skipped int `json:"skipped"`
+}
+
+type ImportOptions struct {
+	ImportID string
+	// Restart сбрасывает сохранённую позицию и начинает импорт с начала файла
+	Restart bool
+	// OnReject вызывается для каждой отклонённой записи
+	OnReject func(ImportRejection)
+	// ReportedLine — последняя строка, отклонение которой уже передано в OnReject прерванным
+	// запуском. Импорт продолжается с сохранённой позиции, которая может быть раньше неё, поэтому
+	// отклонения строк до ReportedLine включительно в OnReject повторно не передаются.
+	// При Restart не учитывается.
+	ReportedLine int
+}
+
+// ImportService загружает заказы из файлов пачками, запоминая позицию
+// последней сохранённой пачки для продолжения после прерывания.
+type ImportService struct {
+	orders      *OrderService
+	checkpoints repository.ImportCheckpointRepository
+	batchSize   int
+
+	mu sync.Mutex
+	// running — идентификаторы выполняемых импортов
+	running map[string]struct{}
+}
+
+func NewImportService(orders *OrderService, checkpoints repository.ImportCheckpointRepository, batchSize int) *ImportService {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	return &ImportService{orders: orders, checkpoints: checkpoints, batchSize: batchSize, running: map[string]struct{}{}}
+}
+
+// Import читает заказы из r, валидирует их и сохраняет валидные пачками. Вместе с каждой
+// пачкой в той же транзакции сохраняется номер последней обработанной строки, поэтому
+// повторный вызов с тем же ImportID продолжит импорт с места остановки.
+// При ошибке возвращается результат, достигнутый до неё. Одновременный импорт с тем же
+// ImportID перезаписывал бы позицию другого, поэтому он отклоняется с domain.ErrConflict.
+func (s *ImportService) Import(ctx context.Context, r importer.Reader, opts ImportOptions) (ImportResult, error) {
+	if opts.ImportID == "" {
+		return ImportResult{}, fmt.Errorf("%w: import id is required", domain.ErrInvalidArgument)
+	}
+	if !s.acquire(opts.ImportID) {
+		return ImportResult{}, fmt.Errorf("%w: import %s is already running", domain.ErrConflict, opts.ImportID)
+	}
+	defer s.release(opts.ImportID)
+	res := ImportResult{ImportID: opts.ImportID}
+	log := logger.FromContext(ctx, s.orders.log).With(zap.String("import_id", opts.ImportID))
+
+	if opts.Restart {
+		if err := s.checkpoints.DeleteCheckpoint(ctx, opts.ImportID); err != nil {
+			return res, fmt.Errorf("failed to reset import checkpoint: %w", err)
+		}
+	}
+	checkpoint, err := s.checkpoints.GetCheckpoint(ctx, opts.ImportID)
+	if err != nil {
+		return res, fmt.Errorf("failed to get import checkpoint: %w", err)
+	}
+	res.ResumedFrom, res.LastLine = checkpoint, checkpoint
+	if checkpoint > 0 {
+		log.Info("resuming import", zap.Int("line", checkpoint))
+	}
+
+	reported := opts.ReportedLine
+	if opts.Restart {
+		reported = 0
+	}
+	reject := func(rej ImportRejection) {
+		res.Rejected++
+		if opts.OnReject != nil && rej.Line > reported {
+			opts.OnReject(rej)
+		}
+	}
+
+	batch := make([]domain.Order, 0, s.batchSize)
+	lastLine := checkpoint
+	flush := func() error {
+		if lastLine == res.LastLine {
+			return nil
+		}
+		if err := s.saveBatch(ctx, opts.ImportID, batch, lastLine); err != nil {
+			return err
+		}
+		res.Imported += len(batch)
+		res.LastLine = lastLine
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return res, fmt.Errorf("failed to read import file: %w", err)
+		}
+		if rec.Line <= checkpoint {
+			res.Skipped++
+			continue
+		}
+		lastLine = rec.Line
+
+		if rec.Err != nil {
+			reject(ImportRejection{Line: rec.Line, OrderUID: rec.Order.OrderUID, Reason: rec.Err.Error()})
+		} else if err := s.orders.Validate(rec.Order); err != nil {
+			reject(ImportRejection{Line: rec.Line, OrderUID: rec.Order.OrderUID, Reason: err.Error()})
+		} else {
+			batch = append(batch, rec.Order)
+		}
+
+		if len(batch) >= s.batchSize {
+			if err := flush(); err != nil {
+				return res, err
+			}
+		}
+	}
+	// Сохраняем хвост и позицию, даже если в конце файла были только отклонённые записи
+	if err := flush(); err != nil {
+		return res, err
+	}
+	log.Info("import finished",
+		zap.Int("imported", res.Imported), zap.Int("rejected", res.Rejected), zap.Int("skipped", res.Skipped))
+	return res, nil
+}
+
+func (s *ImportService) acquire(importID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.running[importID]; ok {
+		return false
+	}
+	s.running[importID] = struct{}{}
+	return true
+}
+
+func (s *ImportService) release(importID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, importID)
+}
+
+// saveBatch сохраняет пачку заказов, события order.stored для outbox и позицию импорта в одной транзакции. После фиксации
+// сохранённые заказы удаляются из кеша, чтобы GET /orders/:order_uid не отдавал версию до импорта,
+// и рассылаются ожидающим публикации и подписчикам ленты так же, как заказы из шины.
+func (s *ImportService) saveBatch(ctx context.Context, importID string, orders []domain.Order, lastLine int) error {
+	// Время изменения задаётся здесь, как в storeOrder, чтобы совпадать с сохранённым в БД
+	updated := time.Now().UTC().Truncate(time.Microsecond)
+	for i := range orders {
+		orders[i].UpdatedAt = updated
+	}
+
+	tx, err := s.orders.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if len(orders) > 0 {
+		if err := s.orders.repo.SaveBatchWithTx(ctx, tx, orders); err != nil {
+			return fmt.Errorf("failed to save orders batch: %w", err)
+		}
+		// События пишутся в той же транзакции, как в storeOrder
+		events := make([]repository.OutboxEvent, 0, len(orders))
+		for _, order := range orders {
+			event, err := orderStoredEvent(order)
+			if err != nil {
+				return fmt.Errorf("failed to build order event: %w", err)
+			}
+			events = append(events, event)
+		}
+		if err := s.orders.outbox.AddBatchWithTx(ctx, tx, events); err != nil {
+			return fmt.Errorf("failed to add order events: %w", err)
+		}
+	}
+	if err := s.checkpoints.SaveCheckpointWithTx(ctx, tx, importID, lastLine); err != nil {
+		return fmt.Errorf("failed to save import checkpoint: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	for _, order := range orders {
+		s.orders.cache.Delete(ctx, order.OrderUID)
+		s.orders.waiters.notify(order.OrderUID, OrderResult{Order: order})
+		s.orders.feed.Publish(order)
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/cache"
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/feed"
+	"wb-l0-go/internal/importer"
+	"wb-l0-go/internal/repository"
+)
+
+// blockingCheckpoints задерживает GetCheckpoint, пока не закрыт release.
+type blockingCheckpoints struct {
+	repository.ImportCheckpointRepository
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingCheckpoints) GetCheckpoint(context.Context, string) (int, error) {
+	b.started <- struct{}{}
+	<-b.release
+	return 0, nil
+}
+
+type emptyReader struct{}
+
+func (emptyReader) Next() (importer.Record, error) { return importer.Record{}, io.EOF }
+
+func TestImport_RejectsConcurrentImportWithSameID(t *testing.T) {
+	checkpoints := &blockingCheckpoints{started: make(chan struct{}, 1), release: make(chan struct{})}
+	svc := NewImportService(NewOrderService(nil, nil, nil, nil, zap.NewNop(), nil, nil), checkpoints, 10)
+	ctx := context.Background()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := svc.Import(ctx, emptyReader{}, ImportOptions{ImportID: "import-1"})
+		done <- err
+	}()
+	<-checkpoints.started
+
+	_, err := svc.Import(ctx, emptyReader{}, ImportOptions{ImportID: "import-1"})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	_, err = svc.Import(ctx, emptyReader{}, ImportOptions{})
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+
+	close(checkpoints.release)
+	require.NoError(t, <-done)
+
+	// После завершения импорт с тем же ID снова доступен
+	_, err = svc.Import(ctx, emptyReader{}, ImportOptions{ImportID: "import-1"})
+	assert.NoError(t, err)
+}
+
+// fakeTx — транзакция, которая ничего не делает.
+type fakeTx struct{ pgx.Tx }
+
+func (fakeTx) Commit(context.Context) error   { return nil }
+func (fakeTx) Rollback(context.Context) error { return nil }
+
+type fakeDB struct{}
+
+func (fakeDB) Begin(context.Context) (pgx.Tx, error) { return fakeTx{}, nil }
+
+// memoryOrders хранит заказы в памяти.
+type memoryOrders struct {
+	repository.OrderRepository
+	orders map[string]domain.Order
+}
+
+func (m *memoryOrders) SaveBatchWithTx(_ context.Context, _ pgx.Tx, orders []domain.Order) error {
+	for _, o := range orders {
+		m.orders[o.OrderUID] = o
+	}
+	return nil
+}
+
+func (m *memoryOrders) Get(_ context.Context, uid string) (domain.Order, error) {
+	o, ok := m.orders[uid]
+	if !ok {
+		return domain.Order{}, domain.ErrNotFound
+	}
+	return o, nil
+}
+
+type memoryOutbox struct {
+	repository.OutboxRepository
+	events []repository.OutboxEvent
+}
+
+func (m *memoryOutbox) AddBatchWithTx(_ context.Context, _ pgx.Tx, events []repository.OutboxEvent) error {
+	m.events = append(m.events, events...)
+	return nil
+}
+
+type memoryCheckpoints struct {
+	repository.ImportCheckpointRepository
+}
+
+func (memoryCheckpoints) GetCheckpoint(context.Context, string) (int, error) { return 0, nil }
+func (memoryCheckpoints) SaveCheckpointWithTx(context.Context, pgx.Tx, string, int) error {
+	return nil
+}
+
+type sliceReader []importer.Record
+
+func (r *sliceReader) Next() (importer.Record, error) {
+	if len(*r) == 0 {
+		return importer.Record{}, io.EOF
+	}
+	rec := (*r)[0]
+	*r = (*r)[1:]
+	return rec, nil
+}
+
+func TestImport_ReplacesCachedOrder(t *testing.T) {
+	ctx := context.Background()
+	old := testOrder("order-1")
+	repo := &memoryOrders{orders: map[string]domain.Order{"order-1": old}}
+	orderCache := cache.NewMemoryCache(10)
+	orderCache.Put(ctx, old)
+	orderFeed := feed.New(10)
+	outbox := &memoryOutbox{}
+	orders := NewOrderService(repo, outbox, orderCache, nil, zap.NewNop(), nil, orderFeed)
+	orders.pool = fakeDB{}
+	_, events, cancel := orderFeed.Subscribe(0)
+	defer cancel()
+
+	imported := testOrder("order-1")
+	imported.TrackNumber = "IMPORTED"
+	res, err := NewImportService(orders, memoryCheckpoints{}, 10).
+		Import(ctx, &sliceReader{{Line: 1, Order: imported}}, ImportOptions{ImportID: "import-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Imported)
+
+	got, err := orders.GetOrder(ctx, "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, "IMPORTED", got.TrackNumber)
+	assert.False(t, got.UpdatedAt.IsZero())
+
+	select {
+	case ev := <-events:
+		assert.Equal(t, "order-1", ev.Order.OrderUID)
+	default:
+		t.Fatal("imported order was not published to the feed")
+	}
+
+	// Импорт пишет события order.stored в outbox
+	require.Len(t, outbox.events, 1)
+	assert.Equal(t, domain.EventOrderStored, outbox.events[0].EventType)
+	assert.Equal(t, "order-1", outbox.events[0].Key)
+}
+
+// testOrder возвращает заказ, проходящий Validate.
+func testOrder(uid string) domain.Order {
+	return domain.Order{
+		OrderUID:        uid,
+		TrackNumber:     "WBILMTESTTRACK",
+		Entry:           "WBIL",
+		Locale:          "en",
+		CustomerID:      "test",
+		DeliveryService: "meest",
+		ShardKey:        "9",
+		SmID:            99,
+		DateCreated:     time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		OofShard:        "1",
+		Delivery: domain.Delivery{
+			Name: "Test Testov", Phone: "+9720000000", Zip: "2639809", City: "Kiryat Mozkin",
+			Address: "Ploshad Mira 15", Region: "Kraiot", Email: "test@gmail.com",
+		},
+		Payment: domain.Payment{
+			Transaction: uid, Currency: "USD", Provider: "wbpay", Amount: 1817, PaymentDt: 1637907727,
+			Bank: "alpha", DeliveryCost: 1500, GoodsTotal: 317,
+		},
+		Items: []domain.Items{{
+			ChrtID: 9934930, TrackNumber: "WBILMTESTTRACK", Price: 453, Rid: "ab4219087a764ae0btest",
+			Name: "Mascaras", TotalPrice: 317, NmID: 2389212, Brand: "Vivienne Sabo", Status: 202,
+		}},
+	}
+}
+
+// savedCheckpoints хранит позиции импортов в памяти.
+type savedCheckpoints struct {
+	repository.ImportCheckpointRepository
+	lines map[string]int
+}
+
+func (c *savedCheckpoints) GetCheckpoint(_ context.Context, id string) (int, error) {
+	return c.lines[id], nil
+}
+
+func (c *savedCheckpoints) SaveCheckpointWithTx(_ context.Context, _ pgx.Tx, id string, line int) error {
+	c.lines[id] = line
+	return nil
+}
+
+// failingReader возвращает записи r, а затем err вместо io.EOF.
+type failingReader struct {
+	sliceReader
+	err error
+}
+
+func (r *failingReader) Next() (importer.Record, error) {
+	rec, err := r.sliceReader.Next()
+	if err == io.EOF {
+		return rec, r.err
+	}
+	return rec, err
+}
+
+func TestImport_ResumeDoesNotReportRejectionsTwice(t *testing.T) {
+	ctx := context.Background()
+	orders := NewOrderService(&memoryOrders{orders: map[string]domain.Order{}}, &memoryOutbox{}, cache.NewMemoryCache(10), nil, zap.NewNop(), nil, feed.New(10))
+	orders.pool = fakeDB{}
+	checkpoints := &savedCheckpoints{lines: map[string]int{}}
+	svc := NewImportService(orders, checkpoints, 2)
+
+	invalid := testOrder("invalid")
+	invalid.TrackNumber = ""
+	file := []importer.Record{
+		{Line: 1, Order: testOrder("order-1")},
+		{Line: 2, Order: invalid},
+		{Line: 3, Order: testOrder("order-3")},
+		{Line: 4, Order: invalid},
+		{Line: 5, Order: testOrder("order-5")},
+		{Line: 6, Order: invalid},
+	}
+	var report []int
+	onReject := func(rej ImportRejection) { report = append(report, rej.Line) }
+
+	// Импорт прерывается после отклонённой строки 4: сохранена позиция пачки до строки 3
+	interrupted := &failingReader{sliceReader: sliceReader(file[:5]), err: errors.New("connection reset")}
+	res, err := svc.Import(ctx, interrupted, ImportOptions{ImportID: "import-1", OnReject: onReject})
+	require.Error(t, err)
+	assert.Equal(t, 3, res.LastLine)
+	assert.Equal(t, []int{2, 4}, report)
+
+	// Продолжение повторно отклоняет строку 4, но в отчёт она уже записана
+	resumed := sliceReader(file)
+	res, err = svc.Import(ctx, &resumed, ImportOptions{ImportID: "import-1", OnReject: onReject, ReportedLine: 4})
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.ResumedFrom)
+	assert.Equal(t, 6, res.LastLine)
+	assert.Equal(t, 1, res.Imported)
+	assert.Equal(t, []int{2, 4, 6}, report)
+}
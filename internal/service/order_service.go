@@ -2,87 +2,362 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
 	"wb-l0-go/internal/cache"
+	"wb-l0-go/internal/codec"
 	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/feed"
+	"wb-l0-go/internal/logger"
 	"wb-l0-go/internal/repository"
 )
 
+var (
+	// ErrOrderMalformed возвращается HandleOrder, если сообщение не удалось разобрать.
+	ErrOrderMalformed = errors.New("failed to unmarshal order")
+	// ErrOrderInvalid возвращается HandleOrder, если заказ не прошёл валидацию.
+	ErrOrderInvalid = errors.New("order validation failed")
+)
+
 type OrderService struct {
-	repo  repository.OrderRepository
-	cache cache.Cache
-	log   *zap.Logger
-	pool  *pgxpool.Pool
+	repo    repository.OrderRepository
+	outbox  repository.OutboxRepository
+	cache   cache.Cache
+	codecs  *codec.Codecs
+	log     *zap.Logger
+	pool    txBeginner
+	feed    *feed.Feed
+	waiters *orderWaiters
+}
+
+// txBeginner начинает транзакцию БД, реализуется *pgxpool.Pool.
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
-func NewOrderService(repo repository.OrderRepository, cache cache.Cache, log *zap.Logger, pool *pgxpool.Pool) *OrderService {
-	return &OrderService{repo: repo, cache: cache, log: log, pool: pool}
+// NewOrderService создаёт сервис заказов. codecs задаёт форматы сообщений шины;
+// если он nil, используются кодеки со встроенной схемой Avro.
+func NewOrderService(repo repository.OrderRepository, outbox repository.OutboxRepository, cache cache.Cache, codecs *codec.Codecs, log *zap.Logger, pool *pgxpool.Pool, feed *feed.Feed) *OrderService {
+	if codecs == nil {
+		codecs = codec.New(nil)
+	}
+	return &OrderService{repo: repo, outbox: outbox, cache: cache, codecs: codecs, log: log, pool: pool, feed: feed, waiters: newOrderWaiters()}
 }
 
-func (s *OrderService) HandleKafkaOrder(ctx context.Context, key string, payload []byte) error {
-	var msg domain.Order
-	if err := json.Unmarshal(payload, &msg); err != nil {
-		s.log.Error("failed to unmarshal order", zap.Error(err))
+// HandleOrder разбирает заказ из сообщения шины, валидирует и сохраняет его.
+// Заголовки сообщения сохраняются вместе с заказом. Поля логов (request_id, correlation_id)
+// берутся из ctx, см. bus.MessageContext.
+func (s *OrderService) HandleOrder(ctx context.Context, env domain.Envelope) error {
+	log := logger.FromContext(ctx, s.log)
+	msg, err := s.decodeOrder(env)
+	if err != nil {
+		log.Error("failed to unmarshal order", zap.String("key", env.Key), zap.Error(err))
+		return err
+	}
+
+	err = s.storeOrder(ctx, msg)
+	// Уведомляем ожидающих результат обработки (синхронная публикация)
+	s.waiters.notify(msg.OrderUID, OrderResult{Order: msg, Err: err})
+	if err != nil {
 		return err
 	}
+	// Рассылаем событие подписчикам live-ленты заказов
+	s.feed.Publish(msg)
+	log.Debug("order stored", zap.String("order_uid", msg.OrderUID), zap.Int("payload_len", len(env.Payload)))
+	return nil
+}
+
+// CheckOrder разбирает и валидирует сообщение так же, как HandleOrder, но ничего не сохраняет.
+func (s *OrderService) CheckOrder(env domain.Envelope) (domain.Order, error) {
+	msg, err := s.decodeOrder(env)
+	if err != nil {
+		return domain.Order{}, err
+	}
+	if err := s.Validate(msg); err != nil {
+		return msg, fmt.Errorf("%w: %w", ErrOrderInvalid, err)
+	}
+	return msg, nil
+}
+
+// DecodeOrder разбирает заказ в формате contentType так же, как тело сообщения шины
+// с этим content-type. Для неизвестного формата возвращает codec.ErrUnsupportedContentType.
+func (s *OrderService) DecodeOrder(contentType string, payload []byte) (domain.Order, error) {
+	return s.codecs.Decode(contentType, payload)
+}
+
+// EncodeOrder кодирует заказ в формате contentType для публикации в шину. Для неизвестного
+// формата возвращает codec.ErrUnsupportedContentType.
+func (s *OrderService) EncodeOrder(contentType string, order domain.Order) ([]byte, error) {
+	c, err := s.codecs.Get(contentType)
+	if err != nil {
+		return nil, err
+	}
+	return c.Encode(order)
+}
+
+// decodeOrder разбирает заказ из сообщения шины в формате, указанном в заголовке content-type.
+// Сообщения без заголовка разбираются как JSON.
+func (s *OrderService) decodeOrder(env domain.Envelope) (domain.Order, error) {
+	msg, err := s.codecs.Decode(env.Header(domain.HeaderContentType), env.Payload)
+	if err != nil {
+		return domain.Order{}, fmt.Errorf("%w: %w", ErrOrderMalformed, err)
+	}
 	// Попробуем заполнить order_uid ключом, если он пуст и ключ задан
-	if msg.OrderUID == "" && key != "" {
-		msg.OrderUID = key
+	if msg.OrderUID == "" && env.Key != "" {
+		msg.OrderUID = env.Key
 	}
+	msg.Headers = env.Headers
+	return msg, nil
+}
 
+// storeOrder валидирует заказ, сохраняет его в БД вместе с событием в outbox и кладёт в кэш.
+func (s *OrderService) storeOrder(ctx context.Context, msg domain.Order) error {
+	log := logger.FromContext(ctx, s.log)
 	// Валидация заказа перед сохранением
 	if err := s.Validate(msg); err != nil {
-		s.log.Error("order validation failed", zap.String("order_uid", msg.OrderUID), zap.Error(err))
-		return fmt.Errorf("order validation failed: %w", err)
+		log.Error("order validation failed", zap.String("order_uid", msg.OrderUID), zap.Error(err))
+		return fmt.Errorf("%w: %w", ErrOrderInvalid, err)
 	}
 
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		s.log.Error("failed to begin transaction", zap.Error(err))
+		log.Error("failed to begin transaction", zap.Error(err))
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	// Сохраняем заказ(транзакция не обязательна, тк делаю вставку только в одну таблицу, но решил оставить)
+	// Время изменения задаётся здесь, чтобы в кэше оно совпадало с сохранённым в БД
+	msg.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
 	if err := s.repo.SaveWithTx(ctx, tx, msg); err != nil {
-		s.log.Error("failed to save order", zap.String("order_uid", msg.OrderUID), zap.Error(err))
+		log.Error("failed to save order", zap.String("order_uid", msg.OrderUID), zap.Error(err))
 		return fmt.Errorf("failed to save order: %w", err)
 	}
+	// Событие пишется в той же транзакции, поэтому оно будет опубликовано тогда и только тогда,
+	// когда заказ сохранён
+	if err := s.addOrderEvent(ctx, tx, msg); err != nil {
+		log.Error("failed to add order event to outbox", zap.String("order_uid", msg.OrderUID), zap.Error(err))
+		return fmt.Errorf("failed to add order event: %w", err)
+	}
 
 	if err := tx.Commit(ctx); err != nil {
-		s.log.Error("failed to commit transaction", zap.Error(err))
+		log.Error("failed to commit transaction", zap.Error(err))
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 	// Кэшируем заказ для быстрого доступа
 	s.cache.Put(ctx, msg)
-	s.log.Debug("order stored", zap.String("order_uid", msg.OrderUID), zap.Int("payload_len", len(payload)))
 	return nil
 }
 
-func (s *OrderService) ListOrdersUIDs(ctx context.Context, limit, offset int) ([]string, error) {
-	if limit <= 0 {
-		limit = 50
+// addOrderEvent записывает событие order.stored в outbox в рамках транзакции tx.
+func (s *OrderService) addOrderEvent(ctx context.Context, tx pgx.Tx, order domain.Order) error {
+	event, err := orderStoredEvent(order)
+	if err != nil {
+		return err
 	}
-	if offset < 0 {
-		offset = 0
+	return s.outbox.AddWithTx(ctx, tx, event)
+}
+
+// orderStoredEvent создаёт событие order.stored для записи в outbox.
+func orderStoredEvent(order domain.Order) (repository.OutboxEvent, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return repository.OutboxEvent{}, err
+	}
+	payload, err := json.Marshal(domain.OrderEvent{
+		EventID:    hex.EncodeToString(id),
+		Type:       domain.EventOrderStored,
+		OrderUID:   order.OrderUID,
+		OccurredAt: time.Now().UTC(),
+		Order:      order,
+	})
+	if err != nil {
+		return repository.OutboxEvent{}, err
 	}
-	return s.repo.ListUIDs(ctx, limit, offset)
+	return repository.OutboxEvent{
+		EventType: domain.EventOrderStored,
+		Key:       order.OrderUID,
+		Payload:   payload,
+	}, nil
 }
 
-func (s *OrderService) ListOrders(ctx context.Context, limit, offset int) ([]domain.Order, error) {
-	if limit <= 0 {
-		limit = 50
+// SubscribeOrders подписывается на ленту новых сохранённых заказов.
+// Если lastEventID не равен нулю, возвращает также пропущенные события из истории ленты.
+func (s *OrderService) SubscribeOrders(lastEventID uint64) ([]feed.Event, <-chan feed.Event, func()) {
+	return s.feed.Subscribe(lastEventID)
+}
+
+// Размер страницы списка заказов, если limit не задан
+const defaultListLimit = 50
+
+// listArgs проверяет параметры списка заказов. Нулевой limit заменяется значением по умолчанию.
+func listArgs(filter domain.OrderFilter, limit, offset int) (int, error) {
+	if limit < 0 {
+		return 0, fmt.Errorf("%w: limit must not be negative", domain.ErrInvalidArgument)
 	}
 	if offset < 0 {
-		offset = 0
+		return 0, fmt.Errorf("%w: offset must not be negative", domain.ErrInvalidArgument)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return 0, fmt.Errorf("%w: from must be before to", domain.ErrInvalidArgument)
+	}
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	return limit, nil
+}
+
+func (s *OrderService) ListOrdersUIDs(ctx context.Context, filter domain.OrderFilter, limit, offset int) ([]string, error) {
+	limit, err := listArgs(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListUIDs(ctx, filter, limit, offset)
+}
+
+// Период статистики по умолчанию, если from не задан
+const defaultStatsPeriod = 30 * 24 * time.Hour
+
+// Максимальное количество интервалов в запрошенном периоде статистики
+const maxStatsBuckets = 2000
+
+// statsArgs проверяет запрос статистики и заполняет значения по умолчанию: интервал — день,
+// конец периода — текущее время, начало — за defaultStatsPeriod до конца.
+func statsArgs(q domain.StatsQuery, now time.Time) (domain.StatsQuery, error) {
+	if q.Interval == "" {
+		q.Interval = domain.StatsDay
+	}
+	step, ok := q.Interval.Duration()
+	if !ok {
+		return q, fmt.Errorf("%w: unknown interval %q", domain.ErrInvalidArgument, q.Interval)
+	}
+	if q.GroupBy != "" && !slices.Contains(domain.StatsGroups, q.GroupBy) {
+		return q, fmt.Errorf("%w: unknown group_by %q", domain.ErrInvalidArgument, q.GroupBy)
+	}
+	if q.Filter.To.IsZero() {
+		q.Filter.To = now.UTC()
+	}
+	if q.Filter.From.IsZero() {
+		q.Filter.From = q.Filter.To.Add(-defaultStatsPeriod)
+	}
+	if !q.Filter.From.Before(q.Filter.To) {
+		return q, fmt.Errorf("%w: from must be before to", domain.ErrInvalidArgument)
+	}
+	if q.Filter.To.Sub(q.Filter.From)/step > maxStatsBuckets {
+		return q, fmt.Errorf("%w: period is too long for interval %s (more than %d buckets)", domain.ErrInvalidArgument, q.Interval, maxStatsBuckets)
+	}
+	return q, nil
+}
+
+// OrderStats возвращает количество заказов и выручку по интервалам date_created.
+// Статистика считается в БД по всем сохранённым заказам, кэш не используется.
+func (s *OrderService) OrderStats(ctx context.Context, q domain.StatsQuery) (domain.OrderStats, error) {
+	q, err := statsArgs(q, time.Now())
+	if err != nil {
+		return domain.OrderStats{}, err
+	}
+	buckets, err := s.repo.Stats(ctx, q)
+	if err != nil {
+		return domain.OrderStats{}, err
+	}
+	return domain.OrderStats{
+		Interval: q.Interval,
+		GroupBy:  q.GroupBy,
+		From:     q.Filter.From.UTC(),
+		To:       q.Filter.To.UTC(),
+		Buckets:  buckets,
+	}, nil
+}
+
+// Количество брендов в сводке покупателя
+const customerTopBrands = 5
+
+// ListCustomerOrders возвращает заказы покупателя, начиная с последних сохранённых.
+func (s *OrderService) ListCustomerOrders(ctx context.Context, customerID string, limit, offset int) ([]domain.Order, error) {
+	if customerID == "" {
+		return nil, fmt.Errorf("%w: customer_id is required", domain.ErrInvalidArgument)
+	}
+	return s.ListOrdersByFilter(ctx, domain.OrderFilter{CustomerID: customerID}, limit, offset)
+}
+
+// CustomerSummary возвращает сводку по заказам покупателя или domain.ErrNotFound, если заказов нет.
+func (s *OrderService) CustomerSummary(ctx context.Context, customerID string) (domain.CustomerSummary, error) {
+	if customerID == "" {
+		return domain.CustomerSummary{}, fmt.Errorf("%w: customer_id is required", domain.ErrInvalidArgument)
+	}
+	return s.repo.CustomerSummary(ctx, customerID, customerTopBrands)
+}
+
+// Максимальное количество заказов в ответе поиска по трек-номеру
+const maxTrackingOrders = 100
+
+// TrackOrders возвращает заказы с трек-номером заказа или товара, либо domain.ErrNotFound.
+func (s *OrderService) TrackOrders(ctx context.Context, trackNumber string) ([]domain.Order, error) {
+	if trackNumber == "" {
+		return nil, fmt.Errorf("%w: track_number is required", domain.ErrInvalidArgument)
+	}
+	orders, err := s.repo.FindByTrackNumber(ctx, trackNumber, maxTrackingOrders)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, fmt.Errorf("track number %s: %w", trackNumber, domain.ErrNotFound)
+	}
+	return orders, nil
+}
+
+// FindItems ищет товары по rid, nm_id и chrt_id. Должно быть задано хотя бы одно условие.
+func (s *OrderService) FindItems(ctx context.Context, q domain.ItemQuery, limit, offset int) ([]domain.ItemMatch, error) {
+	if q.Empty() {
+		return nil, fmt.Errorf("%w: at least one of rid, nm_id and chrt_id is required", domain.ErrInvalidArgument)
+	}
+	limit, err := listArgs(domain.OrderFilter{}, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindItems(ctx, q, limit, offset)
+}
+
+// SearchOrders выполняет полнотекстовый поиск заказов, начиная с самых релевантных.
+func (s *OrderService) SearchOrders(ctx context.Context, query string, limit, offset int) (domain.SearchPage, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return domain.SearchPage{}, fmt.Errorf("%w: query is required", domain.ErrInvalidArgument)
+	}
+	limit, err := listArgs(domain.OrderFilter{}, limit, offset)
+	if err != nil {
+		return domain.SearchPage{}, err
+	}
+	return s.repo.Search(ctx, query, limit, offset)
+}
+
+// ExportOrders вызывает fn для каждого заказа, подходящего под фильтр.
+// Заказы читаются из БД порциями, поэтому выгрузка не ограничена объёмом памяти.
+func (s *OrderService) ExportOrders(ctx context.Context, filter domain.OrderFilter, fn func(domain.Order) error) error {
+	return s.repo.Stream(ctx, filter, fn)
+}
+
+func (s *OrderService) ListOrders(ctx context.Context, limit, offset int) ([]domain.Order, error) {
+	return s.ListOrdersByFilter(ctx, domain.OrderFilter{}, limit, offset)
+}
+
+// ListOrdersByFilter возвращает заказы, подходящие под фильтр, начиная с последних сохранённых.
+func (s *OrderService) ListOrdersByFilter(ctx context.Context, filter domain.OrderFilter, limit, offset int) ([]domain.Order, error) {
+	limit, err := listArgs(filter, limit, offset)
+	if err != nil {
+		return nil, err
 	}
-	return s.repo.List(ctx, limit, offset)
+	return s.repo.List(ctx, filter, limit, offset)
 }
 
 func (s *OrderService) GetOrder(ctx context.Context, orderUID string) (domain.Order, error) {
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/codec"
+	"wb-l0-go/internal/domain"
+)
+
+func TestDecodeOrder_ContentType(t *testing.T) {
+	s := NewOrderService(nil, nil, nil, nil, zap.NewNop(), nil, nil)
+	codecs := codec.New(nil)
+	pb, err := codecs.Get(codec.ContentTypeProtobuf)
+	require.NoError(t, err)
+	payload, err := pb.Encode(domain.Order{TrackNumber: "WBILMTESTTRACK"})
+	require.NoError(t, err)
+
+	headers := map[string]string{"Content-Type": codec.ContentTypeProtobuf, domain.HeaderCorrelationID: "corr-1"}
+	order, err := s.decodeOrder(domain.Envelope{Key: "order-1", Payload: payload, Headers: headers})
+	require.NoError(t, err)
+	assert.Equal(t, "order-1", order.OrderUID)
+	assert.Equal(t, "WBILMTESTTRACK", order.TrackNumber)
+	assert.Equal(t, headers, order.Headers)
+
+	// Без заголовка сообщение разбирается как JSON
+	order, err = s.decodeOrder(domain.Envelope{Payload: []byte(`{"order_uid":"order-2"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, "order-2", order.OrderUID)
+
+	_, err = s.decodeOrder(domain.Envelope{Payload: payload, Headers: map[string]string{domain.HeaderContentType: "text/csv"}})
+	assert.ErrorIs(t, err, ErrOrderMalformed)
+	assert.ErrorIs(t, err, codec.ErrUnsupportedContentType)
+}
+
+func TestListArgs(t *testing.T) {
+	limit, err := listArgs(domain.OrderFilter{}, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, defaultListLimit, limit)
+
+	_, err = listArgs(domain.OrderFilter{}, -1, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+	_, err = listArgs(domain.OrderFilter{}, 10, -1)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	_, err = listArgs(domain.OrderFilter{From: day, To: day}, 10, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+}
+
+func TestStatsArgs(t *testing.T) {
+	now := time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)
+	q, err := statsArgs(domain.StatsQuery{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatsDay, q.Interval)
+	assert.Equal(t, now, q.Filter.To)
+	assert.Equal(t, now.Add(-defaultStatsPeriod), q.Filter.From)
+
+	_, err = statsArgs(domain.StatsQuery{Interval: "month"}, now)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+	_, err = statsArgs(domain.StatsQuery{GroupBy: "city"}, now)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+	_, err = statsArgs(domain.StatsQuery{Filter: domain.OrderFilter{From: now}}, now)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+
+	// Год по часам — больше maxStatsBuckets интервалов
+	year := domain.OrderFilter{From: now.AddDate(-1, 0, 0), To: now}
+	_, err = statsArgs(domain.StatsQuery{Interval: domain.StatsHour, Filter: year}, now)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+	_, err = statsArgs(domain.StatsQuery{Interval: domain.StatsWeek, GroupBy: domain.StatsByBrand, Filter: year}, now)
+	assert.NoError(t, err)
+}
+
+func TestLookup_RequiresCondition(t *testing.T) {
+	s := NewOrderService(nil, nil, nil, nil, zap.NewNop(), nil, nil)
+	_, err := s.FindItems(context.Background(), domain.ItemQuery{}, 10, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+	_, err = s.TrackOrders(context.Background(), "")
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+	_, err = s.SearchOrders(context.Background(), "  ", 10, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/logger"
+)
+
+// Количество последних примеров каждого исхода в сводке теневого режима
+const shadowSamples = 20
+
+// ShadowSample — пример заказа, отклонённого или изменённого в теневом режиме.
+type ShadowSample struct {
+	OrderUID string    `json:"order_uid,omitempty"`
+	Time     time.Time `json:"time"`
+	// Reason — причина отклонения или ошибка
+	Reason string `json:"reason,omitempty"`
+	// Fields — поля верхнего уровня, отличающиеся от сохранённого заказа
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ShadowSummary — сводка того, что сделал бы HandleOrder с обработанными сообщениями.
+type ShadowSummary struct {
+	Since     time.Time `json:"since"`
+	Processed int       `json:"processed"`
+	// New — заказы прошли бы валидацию и были бы сохранены впервые
+	New int `json:"new"`
+	// Unchanged — заказы прошли бы валидацию и совпадают с сохранёнными
+	Unchanged int `json:"unchanged"`
+	// Changed — заказы прошли бы валидацию и перезаписали бы сохранённые
+	Changed int `json:"changed"`
+	// Rejected — сообщения не удалось бы разобрать или они не прошли бы валидацию
+	Rejected int `json:"rejected"`
+	// Failed — не удалось сравнить с сохранёнными данными
+	Failed int `json:"failed"`
+	// ChangedFields — сколько раз менялось каждое поле верхнего уровня
+	ChangedFields    map[string]int `json:"changed_fields"`
+	RecentRejections []ShadowSample `json:"recent_rejections"`
+	RecentChanges    []ShadowSample `json:"recent_changes"`
+}
+
+// ShadowService обрабатывает сообщения так же, как HandleOrder (разбор, заполнение
+// order_uid из ключа, валидация), но ничего не сохраняет, а сравнивает результат
+// с уже сохранёнными заказами и накапливает сводку.
+type ShadowService struct {
+	orders *OrderService
+	log    *zap.Logger
+
+	mu      sync.Mutex
+	summary ShadowSummary
+}
+
+func NewShadowService(orders *OrderService, log *zap.Logger) *ShadowService {
+	return &ShadowService{
+		orders: orders,
+		log:    log,
+		summary: ShadowSummary{
+			Since:            time.Now().UTC(),
+			ChangedFields:    map[string]int{},
+			RecentRejections: []ShadowSample{},
+			RecentChanges:    []ShadowSample{},
+		},
+	}
+}
+
+// HandleOrder имеет ту же сигнатуру, что и OrderService.HandleOrder, и подставляется
+// в consumer вместо него. Ошибки обработки учитываются в сводке и не возвращаются, чтобы
+// consumer коммитил сообщения теневой группы.
+func (s *ShadowService) HandleOrder(ctx context.Context, env domain.Envelope) error {
+	now := time.Now().UTC()
+	log := logger.FromContext(ctx, s.log)
+	order, err := s.orders.CheckOrder(env)
+	if err != nil {
+		s.record(func(sum *ShadowSummary) {
+			sum.Rejected++
+			sum.RecentRejections = pushSample(sum.RecentRejections, ShadowSample{OrderUID: order.OrderUID, Time: now, Reason: err.Error()})
+		})
+		log.Debug("shadow: order would be rejected", zap.String("order_uid", order.OrderUID), zap.Error(err))
+		return nil
+	}
+
+	stored, err := s.orders.repo.Get(ctx, order.OrderUID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		s.record(func(sum *ShadowSummary) { sum.New++ })
+		return nil
+	case err != nil:
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.record(func(sum *ShadowSummary) {
+			sum.Failed++
+			sum.RecentRejections = pushSample(sum.RecentRejections, ShadowSample{OrderUID: order.OrderUID, Time: now, Reason: err.Error()})
+		})
+		log.Error("shadow: failed to get stored order", zap.String("order_uid", order.OrderUID), zap.Error(err))
+		return nil
+	}
+
+	fields, err := diffOrders(stored, order)
+	if err != nil {
+		s.record(func(sum *ShadowSummary) { sum.Failed++ })
+		log.Error("shadow: failed to compare orders", zap.String("order_uid", order.OrderUID), zap.Error(err))
+		return nil
+	}
+	if len(fields) == 0 {
+		s.record(func(sum *ShadowSummary) { sum.Unchanged++ })
+		return nil
+	}
+	s.record(func(sum *ShadowSummary) {
+		sum.Changed++
+		for _, f := range fields {
+			sum.ChangedFields[f]++
+		}
+		sum.RecentChanges = pushSample(sum.RecentChanges, ShadowSample{OrderUID: order.OrderUID, Time: now, Fields: fields})
+	})
+	log.Debug("shadow: order would be changed", zap.String("order_uid", order.OrderUID), zap.Strings("fields", fields))
+	return nil
+}
+
+func (s *ShadowService) record(fn func(*ShadowSummary)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summary.Processed++
+	fn(&s.summary)
+}
+
+// Summary возвращает копию текущей сводки.
+func (s *ShadowService) Summary() ShadowSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sum := s.summary
+	sum.ChangedFields = maps.Clone(s.summary.ChangedFields)
+	sum.RecentRejections = slices.Clone(s.summary.RecentRejections)
+	sum.RecentChanges = slices.Clone(s.summary.RecentChanges)
+	return sum
+}
+
+// ReportLoop пишет сводку в лог раз в interval до отмены контекста и один раз после неё.
+// При interval <= 0 периодическая запись отключена, сводка пишется только при остановке.
+func (s *ShadowService) ReportLoop(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		<-ctx.Done()
+		s.logSummary()
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logSummary()
+			return
+		case <-ticker.C:
+			s.logSummary()
+		}
+	}
+}
+
+func (s *ShadowService) logSummary() {
+	sum := s.Summary()
+	s.log.Info("shadow summary",
+		zap.Time("since", sum.Since),
+		zap.Int("processed", sum.Processed),
+		zap.Int("new", sum.New),
+		zap.Int("unchanged", sum.Unchanged),
+		zap.Int("changed", sum.Changed),
+		zap.Int("rejected", sum.Rejected),
+		zap.Int("failed", sum.Failed),
+		zap.Any("changed_fields", sum.ChangedFields))
+}
+
+func pushSample(samples []ShadowSample, s ShadowSample) []ShadowSample {
+	samples = append(samples, s)
+	if len(samples) > shadowSamples {
+		samples = samples[len(samples)-shadowSamples:]
+	}
+	return samples
+}
+
+// diffOrders возвращает отсортированный список JSON полей верхнего уровня, в которых заказы различаются.
+// Заголовки сообщения не сравниваются: они меняются при каждой публикации.
+func diffOrders(a, b domain.Order) ([]string, error) {
+	a.Headers, b.Headers = nil, nil
+	fa, err := orderFields(a)
+	if err != nil {
+		return nil, err
+	}
+	fb, err := orderFields(b)
+	if err != nil {
+		return nil, err
+	}
+	var diff []string
+	for name, va := range fa {
+		if !bytes.Equal(va, fb[name]) {
+			diff = append(diff, name)
+		}
+	}
+	slices.Sort(diff)
+	return diff, nil
+}
+
+func orderFields(o domain.Order) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
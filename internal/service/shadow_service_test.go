@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"wb-l0-go/internal/domain"
+)
+
+func TestDiffOrders(t *testing.T) {
+	created := time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)
+	stored := domain.Order{
+		OrderUID:    "order-1",
+		TrackNumber: "WBILMTESTTRACK",
+		DateCreated: created,
+		Delivery:    domain.Delivery{City: "Kiryat Mozkin"},
+		Items:       []domain.Items{{ChrtID: 1}},
+	}
+
+	fields, err := diffOrders(stored, stored)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	// Время в другой зоне, но с тем же JSON представлением, изменением не считается
+	same := stored
+	same.DateCreated = created.In(time.FixedZone("", 0))
+	fields, err = diffOrders(stored, same)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	// Заголовки сообщения не сравниваются
+	same.Headers = map[string]string{domain.HeaderCorrelationID: "abc"}
+	fields, err = diffOrders(stored, same)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	changed := stored
+	changed.TrackNumber = "OTHER"
+	changed.Delivery.City = "Moscow"
+	changed.Items = []domain.Items{{ChrtID: 1}, {ChrtID: 2}}
+	fields, err = diffOrders(stored, changed)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"delivery", "items", "track_number"}, fields)
+}
+
+func TestShadowReportLoop_ZeroInterval(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	s := NewShadowService(nil, zap.New(core))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.ReportLoop(ctx, 0)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("report loop did not stop")
+	}
+	// Периодическая запись отключена, сводка пишется один раз при остановке
+	assert.Equal(t, 1, logs.FilterMessage("shadow summary").Len())
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"sync"
+
+	"wb-l0-go/internal/domain"
+)
+
+// OrderResult — результат обработки заказа в HandleOrder.
+// Err == nil означает, что заказ сохранён.
+type OrderResult struct {
+	Order domain.Order
+	Err   error
+}
+
+// orderWaiters хранит подписки на результат обработки заказов по order_uid.
+type orderWaiters struct {
+	mu sync.Mutex
+	m  map[string]map[chan OrderResult]struct{}
+}
+
+func newOrderWaiters() *orderWaiters {
+	return &orderWaiters{m: make(map[string]map[chan OrderResult]struct{})}
+}
+
+func (w *orderWaiters) add(orderUID string) (chan OrderResult, func()) {
+	ch := make(chan OrderResult, 1)
+
+	w.mu.Lock()
+	if w.m[orderUID] == nil {
+		w.m[orderUID] = make(map[chan OrderResult]struct{})
+	}
+	w.m[orderUID][ch] = struct{}{}
+	w.mu.Unlock()
+
+	cancel := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if subs, ok := w.m[orderUID]; ok {
+			delete(subs, ch)
+			if len(subs) == 0 {
+				delete(w.m, orderUID)
+			}
+		}
+	}
+	return ch, cancel
+}
+
+func (w *orderWaiters) notify(orderUID string, res OrderResult) {
+	w.mu.Lock()
+	subs := w.m[orderUID]
+	delete(w.m, orderUID)
+	w.mu.Unlock()
+
+	for ch := range subs {
+		// Канал буферизован и получает ровно одно значение, поэтому отправка не блокируется
+		ch <- res
+	}
+}
+
+// WaitOrder подписывается на результат обработки заказа с указанным order_uid.
+// Подписку нужно оформить до публикации заказа, чтобы не пропустить уведомление.
+// Возвращённую функцию отмены нужно вызвать, если результат больше не нужен.
+func (s *OrderService) WaitOrder(orderUID string) (<-chan OrderResult, func()) {
+	return s.waiters.add(orderUID)
+}
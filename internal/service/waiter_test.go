@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"wb-l0-go/internal/domain"
+)
+
+func TestOrderWaiters_Notify(t *testing.T) {
+	w := newOrderWaiters()
+
+	ch1, cancel1 := w.add("order-1")
+	defer cancel1()
+	ch2, cancel2 := w.add("order-1")
+	defer cancel2()
+	other, cancelOther := w.add("order-2")
+	defer cancelOther()
+
+	w.notify("order-1", OrderResult{Order: domain.Order{OrderUID: "order-1"}})
+
+	// Оба подписчика получают результат
+	assert.Equal(t, "order-1", (<-ch1).Order.OrderUID)
+	assert.Equal(t, "order-1", (<-ch2).Order.OrderUID)
+	// Подписчик другого заказа ничего не получает
+	assert.Len(t, other, 0)
+}
+
+func TestOrderWaiters_Cancel(t *testing.T) {
+	w := newOrderWaiters()
+
+	ch, cancel := w.add("order-1")
+	cancel()
+
+	w.notify("order-1", OrderResult{Err: errors.New("boom")})
+	assert.Len(t, ch, 0)
+	assert.Empty(t, w.m)
+}
@@ -0,0 +1,44 @@
+// Package bus описывает шину сообщений, через которую публикуются и читаются заказы.
+// Реализации: Kafka (transport/kafka), NATS JetStream (transport/nats) и шина в памяти процесса.
+package bus
+
+import (
+	"context"
+
+	"wb-l0-go/internal/domain"
+)
+
+// Message описывает публикуемое сообщение.
+type Message struct {
+	Key   string
+	Value []byte
+	// Headers передаются получателю в domain.Envelope.Headers
+	Headers map[string]string
+}
+
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	// PublishBatch возвращает срез ошибок той же длины, что и msgs: nil означает, что сообщение опубликовано.
+	PublishBatch(ctx context.Context, msgs []Message) []error
+	Close() error
+}
+
+// OrderHandler обрабатывает сообщение с заказом. Реализуется service.OrderService
+// и service.ShadowService (теневой режим без записи).
+type OrderHandler interface {
+	HandleOrder(ctx context.Context, env domain.Envelope) error
+}
+
+// HandlerFunc позволяет использовать функцию как OrderHandler.
+type HandlerFunc func(ctx context.Context, env domain.Envelope) error
+
+func (f HandlerFunc) HandleOrder(ctx context.Context, env domain.Envelope) error {
+	return f(ctx, env)
+}
+
+// Subscriber читает сообщения и передаёт их обработчику, заданному при создании,
+// пока не будет отменён контекст.
+type Subscriber interface {
+	Run(ctx context.Context) error
+	Close() error
+}
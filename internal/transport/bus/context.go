@@ -0,0 +1,28 @@
+package bus
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/requestid"
+)
+
+// MessageContext возвращает контекст обработки сообщения: ID запроса из заголовка request-id
+// и поля request_id и correlation_id для логов consumer'а и сервиса.
+func MessageContext(ctx context.Context, env domain.Envelope) context.Context {
+	var fields []zap.Field
+	if id := env.Header(domain.HeaderRequestID); id != "" {
+		ctx = requestid.WithContext(ctx, id)
+		fields = append(fields, zap.String("request_id", id))
+	}
+	if id := env.Header(domain.HeaderCorrelationID); id != "" {
+		fields = append(fields, zap.String("correlation_id", id))
+	}
+	if len(fields) == 0 {
+		return ctx
+	}
+	return logger.WithFields(ctx, fields...)
+}
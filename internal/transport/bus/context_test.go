@@ -0,0 +1,32 @@
+package bus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/requestid"
+)
+
+func TestMessageContext(t *testing.T) {
+	env := domain.Envelope{Headers: map[string]string{
+		domain.HeaderRequestID:     "req-1",
+		domain.HeaderCorrelationID: "corr-1",
+	}}
+	ctx := MessageContext(context.Background(), env)
+	assert.Equal(t, "req-1", requestid.FromContext(ctx))
+
+	core, logs := observer.New(zap.InfoLevel)
+	logger.FromContext(ctx, zap.New(core)).Info("handled")
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "req-1", fields["request_id"])
+	assert.Equal(t, "corr-1", fields["correlation_id"])
+
+	// Без заголовков контекст не меняется
+	assert.Equal(t, "", requestid.FromContext(MessageContext(context.Background(), domain.Envelope{})))
+}
@@ -0,0 +1,41 @@
+package bus
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"wb-l0-go/internal/domain"
+)
+
+// MaxHeaderValueLen — максимальная длина значения заголовка, переданного клиентом при публикации.
+const MaxHeaderValueLen = 256
+
+// OrderHeaders возвращает заголовки сообщения с заказом, опубликованного по запросу клиента
+// (HTTP или gRPC). contentType — формат тела сообщения, пустой означает JSON. Если correlationID
+// пуст, генерируется новый ID.
+func OrderHeaders(contentType, correlationID, sourceSystem string) (map[string]string, error) {
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	if correlationID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return nil, err
+		}
+		correlationID = hex.EncodeToString(id)
+	}
+	headers := map[string]string{
+		domain.HeaderContentType:   contentType,
+		domain.HeaderCorrelationID: correlationID,
+	}
+	if sourceSystem != "" {
+		headers[domain.HeaderSourceSystem] = sourceSystem
+	}
+	for name, value := range headers {
+		if len(value) > MaxHeaderValueLen {
+			return nil, fmt.Errorf("header %s is too long, max %d bytes", name, MaxHeaderValueLen)
+		}
+	}
+	return headers, nil
+}
@@ -0,0 +1,102 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/logger"
+)
+
+var (
+	// ErrBufferFull возвращается Memory.Publish, если подписчик не успевает разбирать сообщения.
+	ErrBufferFull = errors.New("memory bus buffer is full")
+	// ErrClosed возвращается при публикации в закрытую шину.
+	ErrClosed = errors.New("memory bus is closed")
+)
+
+// Memory — шина сообщений в памяти процесса для тестов и локальной разработки без брокера.
+// Сообщения хранятся в буфере ограниченного размера и доставляются подписчикам по очереди,
+// каждое сообщение получает один подписчик. Сообщения не переживают перезапуск.
+type Memory struct {
+	ch        chan Message
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func NewMemory(capacity int) *Memory {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &Memory{ch: make(chan Message, capacity), closed: make(chan struct{})}
+}
+
+// Publish кладёт сообщение в буфер. Если буфер заполнен, возвращает ErrBufferFull, не блокируясь.
+func (m *Memory) Publish(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case <-m.closed:
+		return ErrClosed
+	default:
+	}
+	select {
+	case m.ch <- msg:
+		return nil
+	default:
+		return ErrBufferFull
+	}
+}
+
+func (m *Memory) PublishBatch(ctx context.Context, msgs []Message) []error {
+	errs := make([]error, len(msgs))
+	for i, msg := range msgs {
+		errs[i] = m.Publish(ctx, msg)
+	}
+	return errs
+}
+
+// Close запрещает публикацию и останавливает подписчиков. Сообщения, оставшиеся в буфере, теряются.
+func (m *Memory) Close() error {
+	m.closeOnce.Do(func() { close(m.closed) })
+	return nil
+}
+
+// Subscriber создаёт подписчика, передающего сообщения шины обработчику h.
+func (m *Memory) Subscriber(h OrderHandler, log *zap.Logger) *MemorySubscriber {
+	return &MemorySubscriber{bus: m, svc: h, log: log}
+}
+
+type MemorySubscriber struct {
+	bus *Memory
+	svc OrderHandler
+	log *zap.Logger
+}
+
+// Run обрабатывает сообщения до отмены контекста или закрытия шины. Как и consumer Kafka,
+// сообщение с ошибкой обработки не доставляется повторно.
+func (s *MemorySubscriber) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Info("context done, stopping memory subscriber")
+			return ctx.Err()
+		case <-s.bus.closed:
+			return nil
+		case m := <-s.bus.ch:
+			env := domain.Envelope{Key: m.Key, Payload: m.Value, Headers: m.Headers}
+			msgCtx := MessageContext(ctx, env)
+			if err := s.svc.HandleOrder(msgCtx, env); err != nil {
+				logger.FromContext(msgCtx, s.log).Error("failed to handle message", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *MemorySubscriber) Close() error {
+	return nil
+}
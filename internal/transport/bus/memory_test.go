@@ -0,0 +1,65 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+)
+
+func TestMemory_PublishSubscribe(t *testing.T) {
+	m := NewMemory(10)
+	received := make(chan domain.Envelope, 10)
+	sub := m.Subscriber(HandlerFunc(func(_ context.Context, env domain.Envelope) error {
+		received <- env
+		if env.Key == "bad" {
+			return errors.New("invalid order")
+		}
+		return nil
+	}), zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sub.Run(ctx) }()
+
+	headers := map[string]string{domain.HeaderCorrelationID: "corr-1"}
+	require.NoError(t, m.Publish(ctx, Message{Key: "a", Value: []byte("1"), Headers: headers}))
+	errs := m.PublishBatch(ctx, []Message{{Key: "bad", Value: []byte("2")}, {Key: "c", Value: []byte("3")}})
+	assert.Equal(t, []error{nil, nil}, errs)
+
+	// Ошибка обработки не останавливает подписчика
+	for i, want := range []string{"a", "bad", "c"} {
+		select {
+		case env := <-received:
+			assert.Equal(t, want, env.Key)
+			if i == 0 {
+				assert.Equal(t, []byte("1"), env.Payload)
+				assert.Equal(t, headers, env.Headers)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("message %s was not delivered", want)
+		}
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestMemory_BufferFullAndClosed(t *testing.T) {
+	m := NewMemory(1)
+	ctx := context.Background()
+
+	require.NoError(t, m.Publish(ctx, Message{Key: "a"}))
+	assert.ErrorIs(t, m.Publish(ctx, Message{Key: "b"}), ErrBufferFull)
+
+	require.NoError(t, m.Close())
+	assert.ErrorIs(t, m.Publish(ctx, Message{Key: "c"}), ErrClosed)
+	// После закрытия шины подписчик завершается без ошибки
+	assert.NoError(t, m.Subscriber(HandlerFunc(func(context.Context, domain.Envelope) error { return nil }), zap.NewNop()).Run(ctx))
+}
@@ -0,0 +1,142 @@
+package bus
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/repository"
+)
+
+// batchPublisher публикует пачку сообщений. Реализуется всеми Publisher.
+type batchPublisher interface {
+	PublishBatch(ctx context.Context, msgs []Message) []error
+}
+
+// OutboxRelayOptions задаёт параметры OutboxRelay.
+type OutboxRelayOptions struct {
+	// PollInterval — пауза между опросами outbox, когда неопубликованных событий нет
+	PollInterval time.Duration
+	// BatchSize — максимальное количество событий, публикуемых за одну транзакцию
+	BatchSize int
+	// Retention — сколько хранить опубликованные события перед удалением
+	Retention time.Duration
+	// CleanupInterval — периодичность удаления опубликованных событий
+	CleanupInterval time.Duration
+	// RetryBackoff — задержка перед первой повторной публикацией события; удваивается с каждой
+	// неудачной попыткой, чтобы событие, которое не удаётся опубликовать, не блокировало остальные
+	RetryBackoff time.Duration
+	// MaxRetryBackoff — верхняя граница задержки между попытками
+	MaxRetryBackoff time.Duration
+}
+
+// OutboxRelay переносит события из таблицы outbox в шину сообщений. Событие помечается опубликованным
+// только после подтверждения публикации, поэтому при сбоях оно может быть опубликовано
+// повторно (at-least-once), но не теряется.
+type OutboxRelay struct {
+	outbox    repository.OutboxRepository
+	publisher batchPublisher
+	opts      OutboxRelayOptions
+	log       *zap.Logger
+}
+
+func NewOutboxRelay(outbox repository.OutboxRepository, publisher batchPublisher, opts OutboxRelayOptions, log *zap.Logger) *OutboxRelay {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 24 * time.Hour
+	}
+	if opts.CleanupInterval <= 0 {
+		opts.CleanupInterval = time.Hour
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = time.Second
+	}
+	if opts.MaxRetryBackoff <= 0 {
+		opts.MaxRetryBackoff = 10 * time.Minute
+	}
+	if opts.MaxRetryBackoff < opts.RetryBackoff {
+		opts.MaxRetryBackoff = opts.RetryBackoff
+	}
+	return &OutboxRelay{outbox: outbox, publisher: publisher, opts: opts, log: log}
+}
+
+// Run публикует события до отмены контекста.
+func (r *OutboxRelay) Run(ctx context.Context) error {
+	poll := time.NewTimer(0)
+	defer poll.Stop()
+	cleanup := time.NewTicker(r.opts.CleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.log.Info("context done, stopping outbox relay")
+			return ctx.Err()
+		case <-cleanup.C:
+			r.cleanup(ctx)
+		case <-poll.C:
+			n, err := r.publishPending(ctx)
+			if err != nil && ctx.Err() == nil {
+				r.log.Error("failed to publish outbox events", zap.Error(err))
+			}
+			// Полная пачка — скорее всего, есть ещё события, забираем их без паузы
+			if err == nil && n == r.opts.BatchSize {
+				poll.Reset(0)
+			} else {
+				poll.Reset(r.opts.PollInterval)
+			}
+		}
+	}
+}
+
+// publishPending публикует одну пачку событий и возвращает количество опубликованных.
+func (r *OutboxRelay) publishPending(ctx context.Context) (int, error) {
+	return r.outbox.PublishPending(ctx, r.opts.BatchSize, func(events []repository.OutboxEvent) (repository.OutboxResult, error) {
+		msgs := make([]Message, 0, len(events))
+		for _, e := range events {
+			msgs = append(msgs, Message{Key: e.Key, Value: e.Payload})
+		}
+		errs := r.publisher.PublishBatch(ctx, msgs)
+
+		var res repository.OutboxResult
+		for i, err := range errs {
+			if err != nil {
+				delay := r.retryDelay(events[i].Attempts)
+				r.log.Warn("failed to publish outbox event, will retry",
+					zap.Int64("event_id", events[i].ID), zap.String("key", events[i].Key),
+					zap.Int("attempts", events[i].Attempts+1), zap.Duration("retry_in", delay), zap.Error(err))
+				res.Retry = append(res.Retry, repository.OutboxRetry{ID: events[i].ID, Delay: delay})
+				continue
+			}
+			res.Published = append(res.Published, events[i].ID)
+		}
+		return res, nil
+	})
+}
+
+// retryDelay возвращает задержку перед следующей попыткой для события, которое уже
+// attempts раз не удалось опубликовать.
+func (r *OutboxRelay) retryDelay(attempts int) time.Duration {
+	delay := r.opts.RetryBackoff
+	for i := 0; i < attempts && delay < r.opts.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.opts.MaxRetryBackoff)
+}
+
+func (r *OutboxRelay) cleanup(ctx context.Context) {
+	deleted, err := r.outbox.DeletePublishedBefore(ctx, time.Now().Add(-r.opts.Retention))
+	if err != nil {
+		r.log.Error("failed to clean up outbox", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		r.log.Info("outbox cleaned up", zap.Int64("deleted", deleted))
+	}
+}
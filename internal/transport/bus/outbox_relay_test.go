@@ -0,0 +1,163 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/repository"
+)
+
+// fakeOutbox хранит события в памяти и повторяет семантику PublishPending.
+type fakeOutbox struct {
+	events    []repository.OutboxEvent
+	published map[int64]bool
+	// now — текущее время outbox; события с nextAttempt позже now не выбираются
+	now         time.Time
+	attempts    map[int64]int
+	nextAttempt map[int64]time.Time
+}
+
+func newFakeOutbox(events ...repository.OutboxEvent) *fakeOutbox {
+	return &fakeOutbox{
+		events:      events,
+		published:   map[int64]bool{},
+		now:         time.Now(),
+		attempts:    map[int64]int{},
+		nextAttempt: map[int64]time.Time{},
+	}
+}
+
+func (f *fakeOutbox) AddWithTx(context.Context, pgx.Tx, repository.OutboxEvent) error {
+	return nil
+}
+
+func (f *fakeOutbox) AddBatchWithTx(context.Context, pgx.Tx, []repository.OutboxEvent) error {
+	return nil
+}
+
+func (f *fakeOutbox) PublishPending(_ context.Context, limit int, publish func([]repository.OutboxEvent) (repository.OutboxResult, error)) (int, error) {
+	var pending []repository.OutboxEvent
+	for _, e := range f.events {
+		if f.published[e.ID] || f.nextAttempt[e.ID].After(f.now) || len(pending) == limit {
+			continue
+		}
+		e.Attempts = f.attempts[e.ID]
+		pending = append(pending, e)
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	res, err := publish(pending)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range res.Published {
+		f.published[id] = true
+	}
+	for _, r := range res.Retry {
+		f.attempts[r.ID]++
+		f.nextAttempt[r.ID] = f.now.Add(r.Delay)
+	}
+	return len(res.Published), nil
+}
+
+func (f *fakeOutbox) DeletePublishedBefore(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+type fakePublisher struct {
+	sent []Message
+	// failKeys — ключи сообщений, публикация которых завершается ошибкой
+	failKeys map[string]bool
+}
+
+func (p *fakePublisher) PublishBatch(_ context.Context, msgs []Message) []error {
+	errs := make([]error, len(msgs))
+	for i, m := range msgs {
+		if p.failKeys[m.Key] {
+			errs[i] = errors.New("broker unavailable")
+			continue
+		}
+		p.sent = append(p.sent, m)
+	}
+	return errs
+}
+
+func TestOutboxRelay_PublishPending(t *testing.T) {
+	outbox := newFakeOutbox(
+		repository.OutboxEvent{ID: 1, Key: "a", Payload: []byte(`{"n":1}`)},
+		repository.OutboxEvent{ID: 2, Key: "b", Payload: []byte(`{"n":2}`)},
+		repository.OutboxEvent{ID: 3, Key: "c", Payload: []byte(`{"n":3}`)},
+	)
+	pub := &fakePublisher{failKeys: map[string]bool{"b": true}}
+	relay := NewOutboxRelay(outbox, pub, OutboxRelayOptions{BatchSize: 10}, zap.NewNop())
+
+	n, err := relay.publishPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.True(t, outbox.published[1])
+	assert.False(t, outbox.published[2], "failed event must stay pending")
+	assert.True(t, outbox.published[3])
+
+	// До истечения задержки событие не выбирается повторно
+	n, err = relay.publishPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// Следующий проход повторяет только неопубликованное событие
+	outbox.now = outbox.now.Add(time.Second)
+	pub.failKeys = nil
+	n, err = relay.publishPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, pub.sent, 3)
+	assert.Equal(t, Message{Key: "b", Value: []byte(`{"n":2}`)}, pub.sent[2])
+}
+
+func TestOutboxRelay_PoisonEventDoesNotBlock(t *testing.T) {
+	outbox := newFakeOutbox(
+		repository.OutboxEvent{ID: 1, Key: "a"},
+		repository.OutboxEvent{ID: 2, Key: "b"},
+		repository.OutboxEvent{ID: 3, Key: "c"},
+		repository.OutboxEvent{ID: 4, Key: "d"},
+	)
+	// Событие "b" не удаётся опубликовать никогда
+	pub := &fakePublisher{failKeys: map[string]bool{"b": true}}
+	relay := NewOutboxRelay(outbox, pub, OutboxRelayOptions{
+		BatchSize:       2,
+		RetryBackoff:    time.Second,
+		MaxRetryBackoff: 4 * time.Second,
+	}, zap.NewNop())
+
+	_, err := relay.publishPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, outbox.nextAttempt[2].Sub(outbox.now))
+
+	// Отложенное событие не занимает место в пачке, следующие события публикуются
+	n, err := relay.publishPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	var keys []string
+	for _, m := range pub.sent {
+		keys = append(keys, m.Key)
+	}
+	assert.Equal(t, []string{"a", "c", "d"}, keys)
+
+	// Задержка растёт с каждой попыткой, но не превышает MaxRetryBackoff
+	for _, want := range []time.Duration{2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		outbox.now = outbox.nextAttempt[2]
+		n, err = relay.publishPending(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+		assert.Equal(t, want, outbox.nextAttempt[2].Sub(outbox.now))
+	}
+	assert.Equal(t, 4, outbox.attempts[2])
+	assert.False(t, outbox.published[2])
+}
@@ -0,0 +1,111 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	gin "github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/service"
+	"wb-l0-go/internal/transport/problem"
+)
+
+// Request — тело запроса GraphQL.
+type Request struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+// Handler обслуживает /graphql.
+type Handler struct {
+	schema graphql.Schema
+	limits Limits
+	log    *zap.Logger
+}
+
+func NewHandler(svc *service.OrderService, limits Limits, log *zap.Logger) (*Handler, error) {
+	schema, err := NewSchema(svc, log)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{schema: schema, limits: limits, log: log}, nil
+}
+
+func (h *Handler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/graphql", h.query)
+	r.POST("/graphql", h.query)
+}
+
+// query выполняет запрос из тела (POST) или из параметров query, variables и operationName (GET).
+// Ошибки разбора, валидации и превышения лимитов возвращаются со статусом 200 в поле errors.
+// Endpoint не версионируется вместе с REST API и описан в неверсионируемой документации Swagger (docs/root).
+//
+// @Summary      GraphQL запрос
+// @Description  Выборочное получение полей заказов. Запросы order(orderUid) и orders(customerId, deliveryService, from, to, limit, offset).
+// @Description  Ошибки разбора, валидации и превышения лимитов глубины и сложности возвращаются со статусом 200 в поле errors.
+// @Tags         graphql
+// @Accept       json
+// @Produce      json
+// @Param        request  body   Request  false  "Запрос (для POST)"
+// @Param        query          query  string  false  "Запрос (для GET)"
+// @Param        variables      query  string  false  "Переменные в JSON (для GET)"
+// @Param        operationName  query  string  false  "Имя операции (для GET)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  problem.Problem
+// @Router       /graphql [post]
+// @Router       /graphql [get]
+func (h *Handler) query(c *gin.Context) {
+	var req Request
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if v := c.Query("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				problem.Error(c, http.StatusBadRequest, "invalid variables: "+err.Error())
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Error(c, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	if req.Query == "" {
+		problem.Error(c, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.execute(c, req))
+}
+
+// execute разбирает и валидирует запрос, проверяет лимиты и только затем выполняет его.
+func (h *Handler) execute(c *gin.Context, req Request) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if v := graphql.ValidateDocument(&h.schema, doc, nil); !v.IsValid {
+		return &graphql.Result{Errors: v.Errors}
+	}
+	if op, _ := operation(doc, req.OperationName); op != nil && op.Operation != ast.OperationTypeQuery {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(gqlerrors.NewFormattedError("only query operations are supported"))}
+	}
+	if err := h.limits.check(doc, req.OperationName, req.Variables); err != nil {
+		logger.FromContext(c.Request.Context(), h.log).Warn("graphql query rejected", zap.Error(err))
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       c.Request.Context(),
+	})
+}
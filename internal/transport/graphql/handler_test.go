@@ -0,0 +1,167 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	gin "github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/cache"
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/repository"
+	"wb-l0-go/internal/service"
+)
+
+// fakeRepo отдаёт заказы из памяти и считает обращения к Get.
+type fakeRepo struct {
+	repository.OrderRepository
+	orders []domain.Order
+	gets   int
+}
+
+func (r *fakeRepo) Get(_ context.Context, orderUID string) (domain.Order, error) {
+	r.gets++
+	for _, o := range r.orders {
+		if o.OrderUID == orderUID {
+			return o, nil
+		}
+	}
+	return domain.Order{}, domain.ErrNotFound
+}
+
+func (r *fakeRepo) ListUIDs(_ context.Context, filter domain.OrderFilter, limit, offset int) ([]string, error) {
+	var res []string
+	for _, o := range r.orders {
+		if filter.CustomerID == "" || o.CustomerID == filter.CustomerID {
+			res = append(res, o.OrderUID)
+		}
+	}
+	return res[min(offset, len(res)):min(offset+limit, len(res))], nil
+}
+
+func newRouter(t *testing.T, repo repository.OrderRepository, limits Limits) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	svc := service.NewOrderService(repo, nil, cache.NewMemoryCache(10), nil, zap.NewNop(), nil, nil)
+	h, err := NewHandler(svc, limits, zap.NewNop())
+	require.NoError(t, err)
+	r := gin.New()
+	h.RegisterRoutes(r)
+	return r
+}
+
+type response struct {
+	Data   map[string]any   `json:"data"`
+	Errors []map[string]any `json:"errors"`
+}
+
+func post(t *testing.T, r http.Handler, req Request) response {
+	t.Helper()
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func testOrders() []domain.Order {
+	return []domain.Order{
+		{OrderUID: "order-1", CustomerID: "alice", Delivery: domain.Delivery{City: "Moscow"},
+			Items: []domain.Items{{ChrtID: 1, Name: "Mascaras"}}},
+		{OrderUID: "order-2", CustomerID: "bob"},
+		{OrderUID: "order-3", CustomerID: "alice"},
+	}
+}
+
+func TestHandler_Order(t *testing.T) {
+	repo := &fakeRepo{orders: testOrders()}
+	r := newRouter(t, repo, Limits{})
+	query := `query($uid: String!) { order(orderUid: $uid) { orderUid delivery { city } items { name } } }`
+
+	resp := post(t, r, Request{Query: query, Variables: map[string]any{"uid": "order-1"}})
+	require.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]any{
+		"orderUid": "order-1",
+		"delivery": map[string]any{"city": "Moscow"},
+		"items":    []any{map[string]any{"name": "Mascaras"}},
+	}, resp.Data["order"])
+
+	// Повторный запрос обслуживается из кэша
+	post(t, r, Request{Query: query, Variables: map[string]any{"uid": "order-1"}})
+	assert.Equal(t, 1, repo.gets)
+
+	resp = post(t, r, Request{Query: query, Variables: map[string]any{"uid": "missing"}})
+	require.Empty(t, resp.Errors)
+	assert.Nil(t, resp.Data["order"])
+}
+
+func TestHandler_OrdersPagination(t *testing.T) {
+	r := newRouter(t, &fakeRepo{orders: testOrders()}, Limits{})
+
+	resp := post(t, r, Request{Query: `{ orders(customerId: "alice", limit: 1) { nodes { orderUid } hasNextPage } }`})
+	require.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]any{
+		"nodes":       []any{map[string]any{"orderUid": "order-1"}},
+		"hasNextPage": true,
+	}, resp.Data["orders"])
+
+	resp = post(t, r, Request{Query: `{ orders(customerId: "alice", limit: 1, offset: 1) { nodes { orderUid } hasNextPage } }`})
+	require.Empty(t, resp.Errors)
+	assert.Equal(t, false, resp.Data["orders"].(map[string]any)["hasNextPage"])
+
+	resp = post(t, r, Request{Query: `{ orders(limit: 1000) { limit } }`})
+	assert.NotEmpty(t, resp.Errors)
+}
+
+func TestHandler_Limits(t *testing.T) {
+	r := newRouter(t, &fakeRepo{orders: testOrders()}, Limits{MaxDepth: 3, MaxComplexity: 100})
+
+	// nodes → items → name: глубина 4
+	resp := post(t, r, Request{Query: `{ orders { nodes { items { name } } } }`})
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0]["message"], "depth")
+	assert.Nil(t, resp.Data)
+
+	// 1 + 50 * (1 + 1) = 101; limit берётся из переменной
+	resp = post(t, r, Request{
+		Query:     `query($n: Int) { orders(limit: $n) { nodes { orderUid } } }`,
+		Variables: map[string]any{"n": 50},
+	})
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0]["message"], "complexity")
+
+	// Фрагменты раскрываются при подсчёте, по умолчанию limit = 20
+	resp = post(t, r, Request{Query: `{ orders { ...page } } fragment page on OrderPage { nodes { orderUid } }`})
+	assert.Empty(t, resp.Errors)
+}
+
+func TestHandler_GetAndBadRequest(t *testing.T) {
+	r := newRouter(t, &fakeRepo{orders: testOrders()}, Limits{})
+
+	q := url.Values{
+		"query":     {`query($uid: String!) { order(orderUid: $uid) { customerId } }`},
+		"variables": {`{"uid":"order-2"}`},
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?"+q.Encode(), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"order":{"customerId":"bob"}}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader([]byte(`{`))))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	resp := post(t, r, Request{Query: `{ order(orderUid: "order-1") { unknown } }`})
+	assert.NotEmpty(t, resp.Errors)
+}
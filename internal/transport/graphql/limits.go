@@ -0,0 +1,134 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Предполагаемое число товаров в заказе при расчёте сложности запроса
+const itemsPerOrder = 10
+
+// Limits ограничивает глубину и сложность запроса. Нулевое значение отключает ограничение.
+type Limits struct {
+	// MaxDepth — максимальная вложенность полей
+	MaxDepth int
+	// MaxComplexity — максимальная оценка числа разрешаемых полей
+	MaxComplexity int
+}
+
+// check оценивает выполняемую операцию документа до её выполнения. Каждое поле стоит 1,
+// стоимость вложенных полей списка умножается на ожидаемое число элементов: limit для
+// orders и itemsPerOrder для items. Документ должен быть уже провалидирован, иначе
+// циклы во фрагментах не исключены.
+func (l Limits) check(doc *ast.Document, operationName string, variables map[string]any) error {
+	op, fragments := operation(doc, operationName)
+	if op == nil {
+		// Ошибку отсутствующей операции вернёт выполнение запроса
+		return nil
+	}
+	a := analyzer{fragments: fragments, variables: variables, defaults: map[string]ast.Value{}}
+	for _, def := range op.VariableDefinitions {
+		if def.Variable != nil && def.DefaultValue != nil {
+			a.defaults[def.Variable.Name.Value] = def.DefaultValue
+		}
+	}
+	depth, complexity := a.selections(op.SelectionSet)
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds limit %d", depth, l.MaxDepth)
+	}
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds limit %d", complexity, l.MaxComplexity)
+	}
+	return nil
+}
+
+func operation(doc *ast.Document, name string) (*ast.OperationDefinition, map[string]*ast.FragmentDefinition) {
+	var op *ast.OperationDefinition
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, def := range doc.Definitions {
+		switch d := def.(type) {
+		case *ast.OperationDefinition:
+			if name == "" || (d.Name != nil && d.Name.Value == name) {
+				op = d
+			}
+		case *ast.FragmentDefinition:
+			fragments[d.Name.Value] = d
+		}
+	}
+	return op, fragments
+}
+
+type analyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	defaults  map[string]ast.Value
+}
+
+// selections возвращает глубину и сложность набора полей.
+func (a analyzer) selections(set *ast.SelectionSet) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, sel := range set.Selections {
+		var d, c int
+		switch s := sel.(type) {
+		case *ast.Field:
+			// Служебные поля интроспекции не учитываются
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			d, c = a.selections(s.SelectionSet)
+			d, c = d+1, 1+a.multiplier(s)*c
+		case *ast.InlineFragment:
+			d, c = a.selections(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if f, ok := a.fragments[s.Name.Value]; ok {
+				d, c = a.selections(f.SelectionSet)
+			}
+		}
+		depth = max(depth, d)
+		complexity += c
+	}
+	return depth, complexity
+}
+
+func (a analyzer) multiplier(f *ast.Field) int {
+	switch f.Name.Value {
+	case "orders":
+		limit := defaultPageSize
+		for _, arg := range f.Arguments {
+			if arg.Name.Value == "limit" {
+				limit = a.int(arg.Value, limit)
+			}
+		}
+		return min(max(limit, 1), maxPageSize)
+	case "items":
+		return itemsPerOrder
+	}
+	return 1
+}
+
+// int возвращает значение целочисленного аргумента, заданного литералом или переменной.
+func (a analyzer) int(v ast.Value, def int) int {
+	switch v := v.(type) {
+	case *ast.IntValue:
+		if n, err := strconv.Atoi(v.Value); err == nil {
+			return n
+		}
+	case *ast.Variable:
+		switch n := a.variables[v.Name.Value].(type) {
+		case int:
+			return n
+		case float64:
+			return int(n)
+		case nil:
+			if d, ok := a.defaults[v.Name.Value]; ok {
+				return a.int(d, def)
+			}
+		}
+	}
+	return def
+}
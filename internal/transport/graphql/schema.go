@@ -0,0 +1,221 @@
+// Package graphql реализует GraphQL API для выборочного получения полей заказов.
+// Запросы разрешаются через service.OrderService, поэтому заказы читаются из кэша,
+// а в БД обращаются только при промахе.
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/service"
+)
+
+const (
+	// Размер страницы orders по умолчанию и максимальный
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// OrderPage — страница списка заказов.
+type OrderPage struct {
+	Nodes       []domain.Order
+	Limit       int
+	Offset      int
+	HasNextPage bool
+}
+
+// field возвращает поле, значение которого вычисляется из объекта-источника типа T.
+func field[T any](typ graphql.Output, get func(T) any) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			src, ok := p.Source.(T)
+			if !ok {
+				return nil, fmt.Errorf("unexpected source %T", p.Source)
+			}
+			return get(src), nil
+		},
+	}
+}
+
+var (
+	str = graphql.NewNonNull(graphql.String)
+	num = graphql.NewNonNull(graphql.Int)
+)
+
+var deliveryType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Delivery",
+	Fields: graphql.Fields{
+		"name":    field(str, func(d domain.Delivery) any { return d.Name }),
+		"phone":   field(str, func(d domain.Delivery) any { return d.Phone }),
+		"zip":     field(str, func(d domain.Delivery) any { return d.Zip }),
+		"city":    field(str, func(d domain.Delivery) any { return d.City }),
+		"address": field(str, func(d domain.Delivery) any { return d.Address }),
+		"region":  field(str, func(d domain.Delivery) any { return d.Region }),
+		"email":   field(str, func(d domain.Delivery) any { return d.Email }),
+	},
+})
+
+var paymentType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Payment",
+	Fields: graphql.Fields{
+		"transaction":  field(str, func(p domain.Payment) any { return p.Transaction }),
+		"requestId":    field(str, func(p domain.Payment) any { return p.RequestId }),
+		"currency":     field(str, func(p domain.Payment) any { return p.Currency }),
+		"provider":     field(str, func(p domain.Payment) any { return p.Provider }),
+		"amount":       field(num, func(p domain.Payment) any { return p.Amount }),
+		"paymentDt":    field(num, func(p domain.Payment) any { return p.PaymentDt }),
+		"bank":         field(str, func(p domain.Payment) any { return p.Bank }),
+		"deliveryCost": field(num, func(p domain.Payment) any { return p.DeliveryCost }),
+		"goodsTotal":   field(num, func(p domain.Payment) any { return p.GoodsTotal }),
+		"customFee":    field(num, func(p domain.Payment) any { return p.CustomFee }),
+	},
+})
+
+var itemType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Item",
+	Fields: graphql.Fields{
+		"chrtId":      field(num, func(i domain.Items) any { return i.ChrtID }),
+		"trackNumber": field(str, func(i domain.Items) any { return i.TrackNumber }),
+		"price":       field(num, func(i domain.Items) any { return i.Price }),
+		"rid":         field(str, func(i domain.Items) any { return i.Rid }),
+		"name":        field(str, func(i domain.Items) any { return i.Name }),
+		"sale":        field(num, func(i domain.Items) any { return i.Sale }),
+		"size":        field(str, func(i domain.Items) any { return i.Size }),
+		"totalPrice":  field(num, func(i domain.Items) any { return i.TotalPrice }),
+		"nmId":        field(num, func(i domain.Items) any { return i.NmID }),
+		"brand":       field(str, func(i domain.Items) any { return i.Brand }),
+		"status":      field(num, func(i domain.Items) any { return i.Status }),
+	},
+})
+
+var orderType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Order",
+	Fields: graphql.Fields{
+		"orderUid":          field(str, func(o domain.Order) any { return o.OrderUID }),
+		"trackNumber":       field(str, func(o domain.Order) any { return o.TrackNumber }),
+		"entry":             field(str, func(o domain.Order) any { return o.Entry }),
+		"delivery":          field(graphql.NewNonNull(deliveryType), func(o domain.Order) any { return o.Delivery }),
+		"payment":           field(graphql.NewNonNull(paymentType), func(o domain.Order) any { return o.Payment }),
+		"items":             field(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(itemType))), func(o domain.Order) any { return o.Items }),
+		"locale":            field(str, func(o domain.Order) any { return o.Locale }),
+		"internalSignature": field(str, func(o domain.Order) any { return o.InternalSig }),
+		"customerId":        field(str, func(o domain.Order) any { return o.CustomerID }),
+		"deliveryService":   field(str, func(o domain.Order) any { return o.DeliveryService }),
+		"shardkey":          field(str, func(o domain.Order) any { return o.ShardKey }),
+		"smId":              field(num, func(o domain.Order) any { return o.SmID }),
+		"dateCreated":       field(graphql.NewNonNull(graphql.DateTime), func(o domain.Order) any { return o.DateCreated }),
+		"oofShard":          field(str, func(o domain.Order) any { return o.OofShard }),
+	},
+})
+
+var orderPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "OrderPage",
+	Fields: graphql.Fields{
+		"nodes":       field(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(orderType))), func(p OrderPage) any { return p.Nodes }),
+		"limit":       field(num, func(p OrderPage) any { return p.Limit }),
+		"offset":      field(num, func(p OrderPage) any { return p.Offset }),
+		"hasNextPage": field(graphql.NewNonNull(graphql.Boolean), func(p OrderPage) any { return p.HasNextPage }),
+	},
+})
+
+// NewSchema создаёт схему GraphQL с запросами order и orders.
+func NewSchema(svc *service.OrderService, log *zap.Logger) (graphql.Schema, error) {
+	r := resolver{svc: svc, log: log}
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"order": &graphql.Field{
+				Type:        orderType,
+				Description: "Заказ по order_uid, null, если заказа нет",
+				Args: graphql.FieldConfigArgument{
+					"orderUid": &graphql.ArgumentConfig{Type: str},
+				},
+				Resolve: r.order,
+			},
+			"orders": &graphql.Field{
+				Type:        graphql.NewNonNull(orderPageType),
+				Description: "Страница заказов, начиная с последних сохранённых",
+				Args: graphql.FieldConfigArgument{
+					"customerId":      &graphql.ArgumentConfig{Type: graphql.String},
+					"deliveryService": &graphql.ArgumentConfig{Type: graphql.String},
+					"from":            &graphql.ArgumentConfig{Type: graphql.DateTime, Description: "date_created не раньше"},
+					"to":              &graphql.ArgumentConfig{Type: graphql.DateTime, Description: "date_created раньше"},
+					"limit":           &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultPageSize},
+					"offset":          &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+				},
+				Resolve: r.orders,
+			},
+		},
+	})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+type resolver struct {
+	svc *service.OrderService
+	log *zap.Logger
+}
+
+func (r resolver) order(p graphql.ResolveParams) (any, error) {
+	uid, _ := p.Args["orderUid"].(string)
+	order, err := r.svc.GetOrder(p.Context, uid)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		logger.FromContext(p.Context, r.log).Error("graphql: failed to get order", zap.String("order_uid", uid), zap.Error(err))
+		return nil, errors.New("failed to get order")
+	}
+	return order, nil
+}
+
+// orders получает uid заказов страницы из БД, а сами заказы — через кэш сервиса.
+// Запрашивается на один uid больше, чтобы определить наличие следующей страницы.
+func (r resolver) orders(p graphql.ResolveParams) (any, error) {
+	limit, _ := p.Args["limit"].(int)
+	offset, _ := p.Args["offset"].(int)
+	if limit < 1 || limit > maxPageSize {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+	if offset < 0 {
+		return nil, errors.New("offset must not be negative")
+	}
+	filter := domain.OrderFilter{}
+	filter.CustomerID, _ = p.Args["customerId"].(string)
+	filter.DeliveryService, _ = p.Args["deliveryService"].(string)
+	if from, ok := p.Args["from"].(time.Time); ok {
+		filter.From = from
+	}
+	if to, ok := p.Args["to"].(time.Time); ok {
+		filter.To = to
+	}
+
+	uids, err := r.svc.ListOrdersUIDs(p.Context, filter, limit+1, offset)
+	if errors.Is(err, domain.ErrInvalidArgument) {
+		return nil, err
+	}
+	if err != nil {
+		logger.FromContext(p.Context, r.log).Error("graphql: failed to list orders", zap.Error(err))
+		return nil, errors.New("failed to list orders")
+	}
+	page := OrderPage{Limit: limit, Offset: offset, HasNextPage: len(uids) > limit, Nodes: []domain.Order{}}
+	for _, uid := range uids[:min(limit, len(uids))] {
+		order, err := r.svc.GetOrder(p.Context, uid)
+		if errors.Is(err, domain.ErrNotFound) {
+			// Заказ удалён между запросами
+			continue
+		}
+		if err != nil {
+			logger.FromContext(p.Context, r.log).Error("graphql: failed to get order", zap.String("order_uid", uid), zap.Error(err))
+			return nil, errors.New("failed to get order")
+		}
+		page.Nodes = append(page.Nodes, order)
+	}
+	return page, nil
+}
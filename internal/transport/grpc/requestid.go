@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/requestid"
+)
+
+// Ключ метаданных с идентификатором запроса, аналог HTTP заголовка X-Request-ID
+var requestIDKey = strings.ToLower(requestid.Header)
+
+// withRequestID берёт идентификатор запроса из метаданных или генерирует новый, кладёт его
+// в контекст вместе с полем request_id для логов и возвращает клиенту в заголовке ответа.
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(requestIDKey); len(v) > 0 {
+			id = v[0]
+		}
+	}
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+	ctx = requestid.WithContext(ctx, id)
+	return logger.WithFields(ctx, zap.String("request_id", id))
+}
+
+func (s *Server) requestIDUnary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(withRequestID(ctx), req)
+}
+
+func (s *Server) requestIDStream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &contextStream{ServerStream: ss, ctx: withRequestID(ss.Context())})
+}
+
+// contextStream подменяет контекст потока.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,247 @@
+// Package grpc реализует gRPC API заказов (order.v1.OrderService) поверх service.OrderService.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"wb-l0-go/internal/codec"
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/logger"
+	orderv1 "wb-l0-go/internal/pb/order/v1"
+	"wb-l0-go/internal/requestid"
+	"wb-l0-go/internal/service"
+	"wb-l0-go/internal/transport/bus"
+)
+
+// Максимальное время ожидания сохранения заказа в PublishOrder
+const maxPublishWait = 30 * time.Second
+
+// Server — gRPC сервер заказов. Работает рядом с HTTP сервером и использует тот же сервис
+// заказов и ту же шину сообщений.
+type Server struct {
+	orderv1.UnimplementedOrderServiceServer
+
+	srv      *grpc.Server
+	service  *service.OrderService
+	producer bus.Publisher
+	log      *zap.Logger
+
+	// closing закрывается при остановке сервера, чтобы завершить бесконечные потоки WatchOrders
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewServer(svc *service.OrderService, prod bus.Publisher, log *zap.Logger) *Server {
+	s := &Server{service: svc, producer: prod, log: log, closing: make(chan struct{})}
+	s.srv = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.requestIDUnary, s.recoverUnary),
+		grpc.ChainStreamInterceptor(s.requestIDStream, s.recoverStream),
+	)
+	orderv1.RegisterOrderServiceServer(s.srv, s)
+	// Reflection позволяет вызывать методы из grpcurl и подобных клиентов без .proto файлов
+	reflection.Register(s.srv)
+	return s
+}
+
+// Serve принимает соединения на lis. Возвращает nil после Shutdown.
+func (s *Server) Serve(lis net.Listener) error {
+	return s.srv.Serve(lis)
+}
+
+// Shutdown завершает потоки WatchOrders, перестаёт принимать новые вызовы и ждёт завершения
+// текущих. Если ctx отменяется раньше, оставшиеся вызовы прерываются.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.closing) })
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.GetOrderResponse, error) {
+	if req.GetOrderUid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_uid is required")
+	}
+	order, err := s.service.GetOrder(ctx, req.GetOrderUid())
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		logger.FromContext(ctx, s.log).Error("failed to get order", zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &orderv1.GetOrderResponse{Order: codec.ToProto(order)}, nil
+}
+
+func (s *Server) ListOrders(ctx context.Context, req *orderv1.ListOrdersRequest) (*orderv1.ListOrdersResponse, error) {
+	filter := domain.OrderFilter{
+		CustomerID:      req.GetCustomerId(),
+		DeliveryService: req.GetDeliveryService(),
+	}
+	if req.GetFrom() != nil {
+		filter.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		filter.To = req.GetTo().AsTime()
+	}
+	orders, err := s.service.ListOrdersByFilter(ctx, filter, int(req.GetLimit()), int(req.GetOffset()))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidArgument) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		logger.FromContext(ctx, s.log).Error("failed to list orders", zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	resp := &orderv1.ListOrdersResponse{Orders: make([]*orderv1.Order, 0, len(orders))}
+	for _, o := range orders {
+		resp.Orders = append(resp.Orders, codec.ToProto(o))
+	}
+	return resp, nil
+}
+
+// PublishOrder публикует заказ в формате content_type (по умолчанию JSON), как POST /publish.
+// С wait дожидается, пока consumer этого же экземпляра приложения сохранит заказ.
+func (s *Server) PublishOrder(ctx context.Context, req *orderv1.PublishOrderRequest) (*orderv1.PublishOrderResponse, error) {
+	var wait time.Duration
+	if req.GetWait() != nil {
+		wait = req.GetWait().AsDuration()
+		if wait <= 0 || wait > maxPublishWait {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid wait: expected duration up to %s", maxPublishWait)
+		}
+	}
+	if req.GetOrder().GetOrderUid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order.order_uid is required")
+	}
+	headers, err := bus.OrderHeaders(req.GetContentType(), req.GetCorrelationId(), req.GetSourceSystem())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		headers[domain.HeaderRequestID] = id
+	}
+	order := codec.FromProto(req.GetOrder())
+	// Заголовки сообщения задаются только полями запроса
+	order.Headers = nil
+	payload, err := s.service.EncodeOrder(req.GetContentType(), order)
+	if errors.Is(err, codec.ErrUnsupportedContentType) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		logger.FromContext(ctx, s.log).Error("failed to encode order", zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	if s.producer == nil {
+		return nil, status.Error(codes.Unavailable, "producer not initialized")
+	}
+
+	// Подписываемся на результат до публикации, чтобы не пропустить уведомление
+	var result <-chan service.OrderResult
+	if wait > 0 {
+		ch, cancel := s.service.WaitOrder(order.OrderUID)
+		defer cancel()
+		result = ch
+	}
+
+	resp := &orderv1.PublishOrderResponse{OrderUid: order.OrderUID, CorrelationId: headers[domain.HeaderCorrelationID]}
+	msg := bus.Message{Key: order.OrderUID, Value: payload, Headers: headers}
+	if err := s.producer.Publish(ctx, msg); err != nil {
+		logger.FromContext(ctx, s.log).Error("failed to publish", zap.String("correlation_id", resp.CorrelationId), zap.Error(err))
+		return nil, status.Error(codes.Unavailable, "failed to publish")
+	}
+	if wait == 0 {
+		return resp, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case res := <-result:
+		switch {
+		case res.Err == nil:
+			resp.Order = codec.ToProto(res.Order)
+			return resp, nil
+		case errors.Is(res.Err, service.ErrOrderInvalid):
+			return nil, status.Error(codes.InvalidArgument, res.Err.Error())
+		default:
+			return nil, status.Error(codes.Internal, "failed to store order")
+		}
+	case <-timer.C:
+		return nil, status.Error(codes.DeadlineExceeded, "published, timeout waiting for order to be stored")
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// WatchOrders передаёт новые сохранённые заказы до отмены вызова или остановки сервера.
+// Если клиент не успевает читать события, поток завершается с кодом UNAVAILABLE и клиент
+// может переподписаться с last_event_id последнего полученного события.
+func (s *Server) WatchOrders(req *orderv1.WatchOrdersRequest, stream grpc.ServerStreamingServer[orderv1.WatchOrdersResponse]) error {
+	backlog, events, cancel := s.service.SubscribeOrders(req.GetLastEventId())
+	defer cancel()
+
+	for _, ev := range backlog {
+		if err := stream.Send(watchResponse(ev.ID, ev.Time, ev.Order)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.closing:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case ev, ok := <-events:
+			if !ok {
+				return status.Error(codes.Unavailable, "subscriber is too slow, resubscribe with last_event_id")
+			}
+			if err := stream.Send(watchResponse(ev.ID, ev.Time, ev.Order)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func watchResponse(id uint64, t time.Time, order domain.Order) *orderv1.WatchOrdersResponse {
+	return &orderv1.WatchOrdersResponse{EventId: id, Time: timestamppb.New(t), Order: codec.ToProto(order)}
+}
+
+// recoverUnary превращает панику обработчика в ошибку INTERNAL, как gin.Recovery для HTTP.
+func (s *Server) recoverUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.FromContext(ctx, s.log).Error("grpc handler panic", zap.String("method", info.FullMethod), zap.Any("panic", r), zap.ByteString("stack", debug.Stack()))
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func (s *Server) recoverStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.FromContext(ss.Context(), s.log).Error("grpc handler panic", zap.String("method", info.FullMethod), zap.Any("panic", r), zap.ByteString("stack", debug.Stack()))
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(srv, ss)
+}
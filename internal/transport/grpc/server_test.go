@@ -0,0 +1,212 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"wb-l0-go/internal/cache"
+	"wb-l0-go/internal/codec"
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/feed"
+	orderv1 "wb-l0-go/internal/pb/order/v1"
+	"wb-l0-go/internal/repository"
+	"wb-l0-go/internal/service"
+	"wb-l0-go/internal/transport/bus"
+)
+
+// fakeRepo отдаёт заказы из памяти. Остальные методы репозитория в тестах не вызываются.
+type fakeRepo struct {
+	repository.OrderRepository
+	orders []domain.Order
+}
+
+func (r *fakeRepo) Get(_ context.Context, orderUID string) (domain.Order, error) {
+	for _, o := range r.orders {
+		if o.OrderUID == orderUID {
+			return o, nil
+		}
+	}
+	return domain.Order{}, domain.ErrNotFound
+}
+
+func (r *fakeRepo) List(_ context.Context, filter domain.OrderFilter, limit, offset int) ([]domain.Order, error) {
+	var res []domain.Order
+	for _, o := range r.orders {
+		if filter.CustomerID == "" || o.CustomerID == filter.CustomerID {
+			res = append(res, o)
+		}
+	}
+	return res[min(offset, len(res)):min(offset+limit, len(res))], nil
+}
+
+func startServer(t *testing.T, repo repository.OrderRepository, prod bus.Publisher, f *feed.Feed) (orderv1.OrderServiceClient, *Server) {
+	t.Helper()
+	svc := service.NewOrderService(repo, nil, cache.NewMemoryCache(10), nil, zap.NewNop(), nil, f)
+	srv := NewServer(svc, prod, zap.NewNop())
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return orderv1.NewOrderServiceClient(conn), srv
+}
+
+func TestServer_GetAndListOrders(t *testing.T) {
+	repo := &fakeRepo{orders: []domain.Order{
+		{OrderUID: "order-1", CustomerID: "alice", TrackNumber: "T1"},
+		{OrderUID: "order-2", CustomerID: "bob"},
+	}}
+	client, _ := startServer(t, repo, nil, feed.New(10))
+	ctx := context.Background()
+
+	resp, err := client.GetOrder(ctx, &orderv1.GetOrderRequest{OrderUid: "order-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "T1", resp.GetOrder().GetTrackNumber())
+
+	_, err = client.GetOrder(ctx, &orderv1.GetOrderRequest{OrderUid: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	list, err := client.ListOrders(ctx, &orderv1.ListOrdersRequest{CustomerId: "bob"})
+	require.NoError(t, err)
+	require.Len(t, list.GetOrders(), 1)
+	assert.Equal(t, "order-2", list.GetOrders()[0].GetOrderUid())
+
+	_, err = client.ListOrders(ctx, &orderv1.ListOrdersRequest{Limit: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_PublishOrder(t *testing.T) {
+	memBus := bus.NewMemory(10)
+	client, _ := startServer(t, &fakeRepo{}, memBus, feed.New(10))
+	ctx := context.Background()
+
+	var header metadata.MD
+	resp, err := client.PublishOrder(metadata.AppendToOutgoingContext(ctx, "x-request-id", "req-1"), &orderv1.PublishOrderRequest{
+		Order:         &orderv1.Order{OrderUid: "order-1", Headers: map[string]string{"ignored": "1"}},
+		CorrelationId: "corr-1",
+		SourceSystem:  "crm",
+	}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-1"}, header.Get("x-request-id"))
+	assert.Equal(t, "order-1", resp.GetOrderUid())
+	assert.Equal(t, "corr-1", resp.GetCorrelationId())
+
+	received := make(chan domain.Envelope, 1)
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		_ = memBus.Subscriber(bus.HandlerFunc(func(_ context.Context, env domain.Envelope) error {
+			received <- env
+			return nil
+		}), zap.NewNop()).Run(subCtx)
+	}()
+	select {
+	case env := <-received:
+		assert.Equal(t, "order-1", env.Key)
+		assert.Equal(t, map[string]string{
+			domain.HeaderContentType:   "application/json",
+			domain.HeaderCorrelationID: "corr-1",
+			domain.HeaderRequestID:     "req-1",
+			domain.HeaderSourceSystem:  "crm",
+		}, env.Headers)
+		var order domain.Order
+		require.NoError(t, json.Unmarshal(env.Payload, &order))
+		assert.Nil(t, order.Headers)
+	case <-time.After(time.Second):
+		t.Fatal("order was not published")
+	}
+
+	_, err = client.PublishOrder(ctx, &orderv1.PublishOrderRequest{Order: &orderv1.Order{}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_PublishOrder_ContentType(t *testing.T) {
+	memBus := bus.NewMemory(10)
+	client, _ := startServer(t, &fakeRepo{}, memBus, feed.New(10))
+	ctx := context.Background()
+
+	_, err := client.PublishOrder(ctx, &orderv1.PublishOrderRequest{
+		Order:       &orderv1.Order{OrderUid: "order-1", TrackNumber: "TRACK"},
+		ContentType: codec.ContentTypeMsgPack,
+	})
+	require.NoError(t, err)
+	select {
+	case msg := <-memBusMessages(t, memBus):
+		assert.Equal(t, codec.ContentTypeMsgPack, msg.Headers[domain.HeaderContentType])
+		order, err := codec.New(nil).Decode(codec.ContentTypeMsgPack, msg.Payload)
+		require.NoError(t, err)
+		assert.Equal(t, "order-1", order.OrderUID)
+		assert.Equal(t, "TRACK", order.TrackNumber)
+	case <-time.After(time.Second):
+		t.Fatal("order was not published")
+	}
+
+	_, err = client.PublishOrder(ctx, &orderv1.PublishOrderRequest{
+		Order:       &orderv1.Order{OrderUid: "order-1"},
+		ContentType: "text/plain",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// memBusMessages передаёт сообщения шины memBus в канал до окончания теста.
+func memBusMessages(t *testing.T, memBus *bus.Memory) <-chan domain.Envelope {
+	t.Helper()
+	ch := make(chan domain.Envelope, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() {
+		_ = memBus.Subscriber(bus.HandlerFunc(func(_ context.Context, env domain.Envelope) error {
+			ch <- env
+			return nil
+		}), zap.NewNop()).Run(ctx)
+	}()
+	return ch
+}
+
+func TestServer_WatchOrders(t *testing.T) {
+	f := feed.New(10)
+	first := f.Publish(domain.Order{OrderUID: "order-1"})
+	client, srv := startServer(t, &fakeRepo{}, nil, f)
+
+	stream, err := client.WatchOrders(context.Background(), &orderv1.WatchOrdersRequest{LastEventId: first.ID - 1})
+	require.NoError(t, err)
+	ev, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, ev.GetEventId())
+	assert.Equal(t, "order-1", ev.GetOrder().GetOrderUid())
+
+	// Подписка оформлена до отправки истории, поэтому новое событие не потеряется
+	f.Publish(domain.Order{OrderUID: "order-2"})
+	ev, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "order-2", ev.GetOrder().GetOrderUid())
+
+	// Остановка сервера завершает поток
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, srv.Shutdown(ctx))
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/service"
+	kafkaTransport "wb-l0-go/internal/transport/kafka"
+	"wb-l0-go/internal/transport/problem"
+)
+
+// AdminHandler обслуживает служебные эндпоинты /admin.
+type AdminHandler struct {
+	// replayer и consumer равны nil, если в качестве шины сообщений используется не Kafka
+	replayer *kafkaTransport.Replayer
+	consumer *kafkaTransport.Consumer
+	// shadow равен nil, если приложение запущено не в теневом режиме
+	shadow *service.ShadowService
+	log    *zap.Logger
+}
+
+func NewAdminHandler(replayer *kafkaTransport.Replayer, consumer *kafkaTransport.Consumer, shadow *service.ShadowService, log *zap.Logger) *AdminHandler {
+	return &AdminHandler{replayer: replayer, consumer: consumer, shadow: shadow, log: log}
+}
+
+func (h *AdminHandler) RegisterRoutes(r gin.IRouter) {
+	admin := r.Group("/admin")
+	admin.POST("/replay", h.replay)
+	admin.GET("/shadow", h.shadowSummary)
+	admin.GET("/consumer", h.consumerStatus)
+	admin.POST("/consumer/pause", h.pauseConsumer)
+	admin.POST("/consumer/resume", h.resumeConsumer)
+	admin.POST("/consumer/drain", h.drainConsumer)
+}
+
+// @Summary      Повторная обработка сообщений Kafka
+// @Description  Читает топик заказов (или DLQ) с заданного offset'а или времени отдельным reader'ом без группы
+// @Description  и прогоняет сообщения через HandleOrder (apply) или только через разбор и валидацию (dry-run).
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        options body kafka.ReplayOptions true "Параметры повторной обработки"
+// @Success      200  {object}  kafka.ReplayReport
+// @Failure      400  {object}  problem.Problem
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem  "Отчёт до ошибки — в поле report"
+// @Router       /admin/replay [post]
+func (h *AdminHandler) replay(c *gin.Context) {
+	if h.replayer == nil {
+		problem.Error(c, http.StatusNotFound, "replay is only available with kafka message bus")
+		return
+	}
+	var opts kafkaTransport.ReplayOptions
+	if err := c.ShouldBindJSON(&opts); err != nil {
+		problem.Error(c, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	report, err := h.replayer.Replay(c.Request.Context(), opts)
+	if err != nil {
+		if errors.Is(err, kafkaTransport.ErrInvalidReplayOptions) {
+			problem.Error(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		logger.FromContext(c.Request.Context(), h.log).Error("replay failed", zap.Error(err))
+		problem.Write(c, problem.New(c.Request, http.StatusInternalServerError, "replay failed").With("report", report))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary      Сводка теневого режима
+// @Description  Что сделал бы consumer с сообщениями, обработанными в теневом режиме (SHADOW_MODE=true):
+// @Description  сколько заказов было бы сохранено впервые, перезаписано с изменениями, осталось бы без изменений или отклонено.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  service.ShadowSummary
+// @Failure      404  {object}  problem.Problem
+// @Router       /admin/shadow [get]
+func (h *AdminHandler) shadowSummary(c *gin.Context) {
+	if h.shadow == nil {
+		problem.Error(c, http.StatusNotFound, "shadow mode is disabled")
+		return
+	}
+	c.JSON(http.StatusOK, h.shadow.Summary())
+}
+
+// Таймаут запросов состояния группы к брокерам в GET /admin/consumer
+const consumerStatusTimeout = 5 * time.Second
+
+// @Summary      Состояние consumer'а Kafka
+// @Description  Назначенные партиции, обработанные и закоммиченные offset'ы, high watermark'и, лаг, время последнего сообщения,
+// @Description  количество ошибок и признак паузы. Если брокеры недоступны, возвращаются внутренние счётчики и broker_error.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  kafka.ConsumerStatus
+// @Failure      404  {object}  problem.Problem
+// @Router       /admin/consumer [get]
+func (h *AdminHandler) consumerStatus(c *gin.Context) {
+	if h.consumer == nil {
+		problem.Error(c, http.StatusNotFound, "consumer status is only available with kafka message bus")
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), consumerStatusTimeout)
+	defer cancel()
+	c.JSON(http.StatusOK, h.consumer.Status(ctx))
+}
+
+// @Summary      Приостановить чтение заказов
+// @Description  Consumer дообрабатывает текущее сообщение и перестаёт читать новые, оставаясь в группе.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  problem.Problem
+// @Router       /admin/consumer/pause [post]
+func (h *AdminHandler) pauseConsumer(c *gin.Context) {
+	if h.consumer == nil {
+		problem.Error(c, http.StatusNotFound, "consumer control is only available with kafka message bus")
+		return
+	}
+	h.consumer.Pause()
+	c.JSON(http.StatusOK, gin.H{"status": "paused"})
+}
+
+// @Summary      Возобновить чтение заказов
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem
+// @Router       /admin/consumer/resume [post]
+func (h *AdminHandler) resumeConsumer(c *gin.Context) {
+	if h.consumer == nil {
+		problem.Error(c, http.StatusNotFound, "consumer control is only available with kafka message bus")
+		return
+	}
+	if err := h.consumer.Resume(); err != nil {
+		problem.Error(c, http.StatusConflict, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "running"})
+}
+
+// @Summary      Drain consumer'а
+// @Description  Останавливает чтение, дожидается обработки и коммита текущего сообщения и выводит consumer из группы.
+// @Description  После этого /readyz отвечает 503. Возобновить чтение можно только перезапуском приложения.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /admin/consumer/drain [post]
+func (h *AdminHandler) drainConsumer(c *gin.Context) {
+	if h.consumer == nil {
+		problem.Error(c, http.StatusNotFound, "consumer control is only available with kafka message bus")
+		return
+	}
+	if err := h.consumer.Drain(); err != nil {
+		logger.FromContext(c.Request.Context(), h.log).Error("failed to drain consumer", zap.Error(err))
+		problem.Error(c, http.StatusInternalServerError, "failed to drain consumer")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "drained"})
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// orderETag возвращает сильный ETag представления заказа — хэш JSON тела ответа. Тело
+// заново сериализуется из заказа, поэтому ETag меняется вместе с любым полем ответа,
+// а не только с payload в БД, и при изменении формата JSON меняется у всех заказов.
+func orderETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified проверяет условные заголовки запроса. If-None-Match имеет приоритет,
+// If-Modified-Since учитывается только без него (RFC 9110, раздел 13.2.2).
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Values("If-None-Match"); len(inm) > 0 {
+		return etagMatch(strings.Join(inm, ","), etag)
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !modified.IsZero() {
+		t, err := http.ParseTime(ims)
+		// Last-Modified передаётся с точностью до секунды
+		return err == nil && !modified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// etagMatch выполняет слабое сравнение списка ETag из If-None-Match с etag.
+func etagMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
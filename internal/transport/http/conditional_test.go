@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/cache"
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/service"
+)
+
+func TestOrderETag(t *testing.T) {
+	etag := orderETag([]byte(`{"order_uid":"a"}`))
+	assert.Equal(t, etag, orderETag([]byte(`{"order_uid":"a"}`)))
+	assert.NotEqual(t, etag, orderETag([]byte(`{"order_uid":"b"}`)))
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 12, 0, 0, 500_000_000, time.UTC)
+	const etag = `"abc"`
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "no conditions", want: false},
+		{name: "etag match", headers: map[string]string{"If-None-Match": `"abc"`}, want: true},
+		{name: "etag in list", headers: map[string]string{"If-None-Match": `"x", W/"abc"`}, want: true},
+		{name: "wildcard", headers: map[string]string{"If-None-Match": `*`}, want: true},
+		{name: "etag mismatch", headers: map[string]string{"If-None-Match": `"x"`}, want: false},
+		{name: "not modified since", headers: map[string]string{"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"}, want: true},
+		{name: "modified since", headers: map[string]string{"If-Modified-Since": "Wed, 01 May 2024 11:59:59 GMT"}, want: false},
+		{name: "invalid date", headers: map[string]string{"If-Modified-Since": "yesterday"}, want: false},
+		{
+			name: "etag takes precedence",
+			headers: map[string]string{
+				"If-None-Match":     `"x"`,
+				"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT",
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/orders/a", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			assert.Equal(t, tt.want, notModified(r, etag, modified))
+		})
+	}
+}
+
+func TestGetOrder_Conditional(t *testing.T) {
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	orders := cache.NewMemoryCache(10)
+	orders.Put(t.Context(), domain.Order{OrderUID: "order-1", TrackNumber: "TRACK", UpdatedAt: updated})
+
+	r := newTestRouter()
+	h := NewHandler(service.NewOrderService(nil, nil, orders, nil, zap.NewNop(), nil, nil), nil, nil, 0, time.Minute, zap.NewNop())
+	r.GET("/orders/:order_uid", h.getOrder)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/order-1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "private, max-age=60, must-revalidate", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", w.Header().Get("Last-Modified"))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/order-1", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	// Другой ETag — заказ отдаётся полностью
+	req = httptest.NewRequest(http.MethodGet, "/orders/order-1", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"order_uid":"order-1"`)
+}
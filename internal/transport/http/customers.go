@@ -0,0 +1,64 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	gin "github.com/gin-gonic/gin"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/transport/problem"
+)
+
+// @Summary      Заказы покупателя
+// @Description  Заказы покупателя целиком, начиная с последних сохранённых.
+// @Tags         customers
+// @Produce      json
+// @Param        customer_id  path   string  true   "ID покупателя"
+// @Param        limit        query  int     false  "Limit"  default(50)
+// @Param        offset       query  int     false  "Offset"
+// @Success      200  {array}   domain.Order
+// @Failure      400  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /customers/{customer_id}/orders [get]
+func (h *Handler) customerOrders(c *gin.Context) {
+	limit, err := queryInt(c, "limit", 50)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	offset, err := queryInt(c, "offset", 0)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	orders, err := h.service.ListCustomerOrders(c.Request.Context(), c.Param("customer_id"), limit, offset)
+	if err != nil {
+		serviceError(c, h.log, "failed to list customer orders", err)
+		return
+	}
+	c.JSON(http.StatusOK, orders)
+}
+
+// @Summary      Сводка по покупателю
+// @Description  Количество заказов, сумма оплат по валютам, даты первого и последнего заказа
+// @Description  и бренды с наибольшим количеством купленных товаров.
+// @Tags         customers
+// @Produce      json
+// @Param        customer_id  path  string  true  "ID покупателя"
+// @Success      200  {object}  domain.CustomerSummary
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /customers/{customer_id}/summary [get]
+func (h *Handler) customerSummary(c *gin.Context) {
+	sum, err := h.service.CustomerSummary(c.Request.Context(), c.Param("customer_id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			problem.Error(c, http.StatusNotFound, "customer has no orders")
+			return
+		}
+		serviceError(c, h.log, "failed to get customer summary", err)
+		return
+	}
+	c.JSON(http.StatusOK, sum)
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/export"
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/transport/problem"
+)
+
+// @Summary      Выгрузка заказов
+// @Description  Потоковая выгрузка заказов в CSV, NDJSON или XLSX. В CSV и XLSX каждая строка соответствует
+// @Description  одному товару заказа. Фильтры совпадают с фильтрами списка заказов.
+// @Tags         orders
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format            query  string  false  "Формат выгрузки: csv, ndjson или xlsx"  default(csv)
+// @Param        customer_id       query  string  false  "Фильтр по customer_id"
+// @Param        delivery_service  query  string  false  "Фильтр по delivery_service"
+// @Param        from              query  string  false  "date_created не раньше (RFC3339 или YYYY-MM-DD)"
+// @Param        to                query  string  false  "date_created раньше (RFC3339 или YYYY-MM-DD)"
+// @Success      200  {file}  file
+// @Failure      400  {object}  problem.Problem
+// @Router       /orders/export [get]
+func (h *Handler) exportOrders(c *gin.Context) {
+	format, err := export.ParseFormat(c.DefaultQuery("format", string(export.FormatCSV)))
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("orders-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	w, err := export.NewWriter(format, c.Writer)
+	if err != nil {
+		logger.FromContext(c.Request.Context(), h.log).Error("failed to start export", zap.Error(err))
+		return
+	}
+	err = h.service.ExportOrders(c.Request.Context(), filter, func(order domain.Order) error {
+		if err := w.Write(order); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		// Заголовки уже отправлены, поэтому сообщить клиенту об ошибке можно только обрывом выгрузки
+		logger.FromContext(c.Request.Context(), h.log).Error("failed to export orders", zap.String("format", string(format)), zap.Error(err))
+		return
+	}
+	if err := w.Close(); err != nil {
+		logger.FromContext(c.Request.Context(), h.log).Error("failed to finish export", zap.String("format", string(format)), zap.Error(err))
+	}
+}
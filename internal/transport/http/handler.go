@@ -2,42 +2,113 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
-
-	_ "wb-l0-go/docs"
+	"strings"
+	"sync"
+	"time"
 
 	gin "github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
 
+	docsroot "wb-l0-go/docs/root"
+	docsv1 "wb-l0-go/docs/v1"
+	"wb-l0-go/internal/codec"
 	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/logger"
 	"wb-l0-go/internal/service"
-	kafkaTransport "wb-l0-go/internal/transport/kafka"
+	"wb-l0-go/internal/transport/bus"
+	"wb-l0-go/internal/transport/problem"
 )
 
+// Максимальное время ожидания сохранения заказа в POST /publish?wait=
+const maxPublishWait = 30 * time.Second
+
+// Максимальный размер тела запроса POST /publish
+const maxPublishBodyBytes = 1 << 20
+
 type Handler struct {
-	service  *service.OrderService
-	log      *zap.Logger
-	producer *kafkaTransport.Producer
+	service   *service.OrderService
+	importer  *service.ImportService
+	log       *zap.Logger
+	producer  bus.Publisher
+	heartbeat time.Duration
+	// cacheControl — значение Cache-Control ответа GET /orders/:order_uid
+	cacheControl string
+	// done закрывается при остановке сервера и завершает открытые SSE и WebSocket потоки
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewHandler создаёт обработчик HTTP API заказов. orderMaxAge задаёт, сколько клиент может
+// использовать полученный заказ без повторной проверки. Заказ содержит персональные данные,
+// поэтому ответ разрешено хранить только в кэше клиента, но не в общих кэшах (CDN, прокси).
+func NewHandler(svc *service.OrderService, importSvc *service.ImportService, prod bus.Publisher, heartbeat, orderMaxAge time.Duration, log *zap.Logger) *Handler {
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	return &Handler{
+		service:      svc,
+		importer:     importSvc,
+		producer:     prod,
+		heartbeat:    heartbeat,
+		cacheControl: fmt.Sprintf("private, max-age=%d, must-revalidate", int(max(orderMaxAge, 0).Seconds())),
+		log:          log,
+		done:         make(chan struct{}),
+	}
 }
 
-func NewHandler(svc *service.OrderService, prod *kafkaTransport.Producer, log *zap.Logger) *Handler {
-	return &Handler{service: svc, producer: prod, log: log}
+// CloseStreams завершает открытые SSE и WebSocket потоки заказов. Вызывается при остановке
+// сервера: http.Server.Shutdown не дожидается hijacked WebSocket соединений, а открытые SSE
+// потоки иначе держали бы его до истечения таймаута.
+func (h *Handler) CloseStreams() {
+	h.closeOnce.Do(func() { close(h.done) })
 }
 
-func (h *Handler) RegisterRoutes(r *gin.Engine) {
+// RegisterUI регистрирует фронтенд и Swagger UI. Документация каждой версии API доступна
+// по /swagger/<версия>/index.html, неверсионируемых GraphQL и проб — по /swagger/root/index.html,
+// /swagger/ перенаправляет на последнюю версию.
+func (h *Handler) RegisterUI(r *gin.Engine) {
 	r.Static("/static", "./internal/frontend")
 	r.GET("/", func(c *gin.Context) {
 		c.File("./internal/frontend/index.html")
 	})
 
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	swaggerV1 := ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.InstanceName(docsv1.SwaggerInfov1.InstanceName()))
+	swaggerRoot := ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.InstanceName(docsroot.SwaggerInforoot.InstanceName()))
+	r.GET("/swagger/*any", func(c *gin.Context) {
+		switch path := c.Param("any"); {
+		case strings.HasPrefix(path, "/v1/"):
+			swaggerV1(c)
+		case strings.HasPrefix(path, "/root/"):
+			swaggerRoot(c)
+		default:
+			c.Redirect(http.StatusFound, "/swagger/v1/index.html")
+		}
+	})
+}
+
+// RegisterRoutes регистрирует маршруты API заказов относительно группы версии.
+func (h *Handler) RegisterRoutes(r gin.IRouter) {
 	r.GET("/orders", h.listOrders)
+	r.GET("/orders/stream", h.streamOrders)
+	r.GET("/orders/ws", h.streamOrdersWS)
+	r.GET("/orders/export", h.exportOrders)
+	r.POST("/orders/import", h.importOrders)
+	r.GET("/orders/search", h.searchOrders)
 	r.GET("/orders/:order_uid", h.getOrder)
 	r.POST("/publish", h.publish)
+	r.POST("/publish/batch", h.publishBatch)
+	r.GET("/customers/:customer_id/orders", h.customerOrders)
+	r.GET("/customers/:customer_id/summary", h.customerSummary)
+	r.GET("/tracking/:track_number", h.trackOrders)
+	r.GET("/items", h.findItems)
+	r.GET("/stats/orders", h.orderStats)
 }
 
 // @Summary      Список uid заказов
@@ -47,79 +118,243 @@ func (h *Handler) RegisterRoutes(r *gin.Engine) {
 // @Produce      json
 // @Param        limit  query    int  false  "Limit"
 // @Param        offset query    int  false  "Offset"
+// @Param        customer_id       query  string  false  "Фильтр по customer_id"
+// @Param        delivery_service  query  string  false  "Фильтр по delivery_service"
+// @Param        from              query  string  false  "date_created не раньше (RFC3339 или YYYY-MM-DD)"
+// @Param        to                query  string  false  "date_created раньше (RFC3339 или YYYY-MM-DD)"
 // @Success      200  {array}  string
-// @Failure      500  {object}  map[string]interface{}
+// @Failure      400  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /orders [get]
 func (h *Handler) listOrders(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	orders, err := h.service.ListOrdersUIDs(c.Request.Context(), limit, offset)
+	limit, err := queryInt(c, "limit", 50)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	offset, err := queryInt(c, "offset", 0)
 	if err != nil {
-		h.log.Error("failed to list orders", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	orders, err := h.service.ListOrdersUIDs(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		serviceError(c, h.log, "failed to list orders", err)
 		return
 	}
 	c.JSON(http.StatusOK, orders)
 }
 
 // @Summary      Получить заказ по uid
-// @Description  Получить заказ по uid
+// @Description  Получить заказ по uid. Ответ содержит ETag (хэш сохранённого заказа), Last-Modified (время
+// @Description  последнего сохранения) и Cache-Control. Если заказ не изменился, условный запрос
+// @Description  с If-None-Match или If-Modified-Since получает 304 без тела.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
 // @Param        order_uid  path    string  true  "Order UID"
+// @Param        If-None-Match      header  string  false  "ETag ранее полученного заказа"
+// @Param        If-Modified-Since  header  string  false  "Last-Modified ранее полученного заказа"
 // @Success      200  {object}  domain.Order
-// @Failure      500  {object}  map[string]interface{}
+// @Header       200  {string}  ETag           "Сильный ETag заказа"
+// @Header       200  {string}  Last-Modified  "Время последнего сохранения заказа"
+// @Header       200  {string}  Cache-Control  "Политика кэширования"
+// @Success      304  "Заказ не изменился"
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
 // @Router       /orders/{order_uid} [get]
 func (h *Handler) getOrder(c *gin.Context) {
 	orderUID := c.Param("order_uid")
 	order, err := h.service.GetOrder(c.Request.Context(), orderUID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		if errors.Is(err, domain.ErrNotFound) {
+			problem.Error(c, http.StatusNotFound, "order not found")
 			return
 		}
-		h.log.Error("failed to get order", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		serviceError(c, h.log, "failed to get order", err)
+		return
+	}
+
+	body, err := json.Marshal(order)
+	if err != nil {
+		logger.FromContext(c.Request.Context(), h.log).Error("failed to encode order", zap.String("order_uid", orderUID), zap.Error(err))
+		problem.Error(c, http.StatusInternalServerError, "internal error")
 		return
 	}
-	c.JSON(http.StatusOK, order)
+	etag := orderETag(body)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", h.cacheControl)
+	if !order.UpdatedAt.IsZero() {
+		c.Header("Last-Modified", order.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+	if notModified(c.Request, etag, order.UpdatedAt) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
 }
 
 // @Summary      Опубликовать заказ
-// @Description  Опубликовать заказ в Kafka
+// @Description  Опубликовать заказ в Kafka. С параметром wait (например, 5s) обработчик дожидается,
+// @Description  пока consumer сохранит заказ, и возвращает сохранённый заказ или ошибку валидации.
+// @Description  X-Correlation-ID и X-Source-System передаются в заголовках сообщения и сохраняются вместе
+// @Description  с заказом. Если X-Correlation-ID не задан, он генерируется и возвращается в ответе.
+// @Description  Тело публикуется без изменений, Content-Type запроса задаёт content-type сообщения: JSON (по умолчанию),
+// @Description  protobuf, Avro или MessagePack. Для неподдерживаемого формата возвращается 415.
 // @Tags         orders
 // @Accept       json
+// @Accept       application/x-protobuf
+// @Accept       application/avro
+// @Accept       application/msgpack
 // @Produce      json
 // @Param        order body domain.Order true "Order"
+// @Param        wait  query    string  false  "Максимальное время ожидания сохранения заказа (например, 5s, не больше 30s)"
+// @Param        X-Correlation-ID  header  string  false  "ID для сквозной трассировки заказа"
+// @Param        X-Source-System   header  string  false  "Система, публикующая заказ"
+// @Success      200  {object}  domain.Order
 // @Success      202  {object}  map[string]interface{}
-// @Failure      400  {object}  map[string]interface{}
-// @Failure      500  {object}  map[string]interface{}
+// @Failure      400  {object}  problem.Problem
+// @Failure      413  {object}  problem.Problem
+// @Failure      415  {object}  problem.Problem
+// @Failure      422  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Failure      502  {object}  problem.Problem
+// @Failure      503  {object}  problem.Problem
+// @Failure      504  {object}  problem.Problem
 // @Router       /publish [post]
 func (h *Handler) publish(c *gin.Context) {
-	var order domain.Order
-	if err := c.ShouldBindJSON(&order); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON"})
+	var wait time.Duration
+	if raw := c.Query("wait"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 || d > maxPublishWait {
+			problem.Error(c, http.StatusBadRequest, "invalid wait: expected duration up to "+maxPublishWait.String())
+			return
+		}
+		wait = d
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPublishBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			problem.Error(c, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		problem.Error(c, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	// Тело публикуется как есть, поэтому разбираем его тем же кодеком, что и consumer:
+	// заказ, принятый здесь, будет разобран и при обработке сообщения
+	contentType := c.GetHeader("Content-Type")
+	order, err := h.service.DecodeOrder(contentType, payload)
+	if err != nil {
+		if errors.Is(err, codec.ErrUnsupportedContentType) {
+			problem.Error(c, http.StatusUnsupportedMediaType, err.Error())
+			return
+		}
+		problem.Error(c, http.StatusBadRequest, "invalid order payload: "+err.Error())
 		return
 	}
 	if order.OrderUID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "order_uid is required"})
+		problem.Error(c, http.StatusBadRequest, "order_uid is required")
 		return
 	}
-	payload, err := json.Marshal(order)
+	headers, err := publishHeaders(c.Request, contentType)
 	if err != nil {
-		h.log.Error("failed to marshal order", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+		problem.Error(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	correlationID := headers[domain.HeaderCorrelationID]
+	c.Header(correlationIDHeader, correlationID)
 	if h.producer == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "producer not initialized"})
+		problem.Error(c, http.StatusServiceUnavailable, "producer not initialized")
+		return
+	}
+
+	// Подписываемся на результат до публикации, чтобы не пропустить уведомление
+	var result <-chan service.OrderResult
+	if wait > 0 {
+		ch, cancel := h.service.WaitOrder(order.OrderUID)
+		defer cancel()
+		result = ch
+	}
+
+	msg := bus.Message{Key: order.OrderUID, Value: payload, Headers: headers}
+	if err := h.producer.Publish(c.Request.Context(), msg); err != nil {
+		logger.FromContext(c.Request.Context(), h.log).Error("failed to publish", zap.String("correlation_id", correlationID), zap.Error(err))
+		problem.Error(c, http.StatusBadGateway, "failed to publish")
 		return
 	}
-	if err := h.producer.Publish(c.Request.Context(), order.OrderUID, payload); err != nil {
-		h.log.Error("failed to publish", zap.Error(err))
-		c.JSON(http.StatusBadGateway, gin.H{"error": "failed to publish"})
+	if wait == 0 {
+		c.JSON(http.StatusAccepted, gin.H{"status": "published", "order_uid": order.OrderUID, "correlation_id": correlationID})
 		return
 	}
-	c.JSON(http.StatusAccepted, gin.H{"status": "published", "order_uid": order.OrderUID})
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case res := <-result:
+		switch {
+		case res.Err == nil:
+			c.JSON(http.StatusOK, res.Order)
+		case errors.Is(res.Err, service.ErrOrderInvalid):
+			problem.Write(c, problem.New(c.Request, http.StatusUnprocessableEntity, res.Err.Error()).
+				With("order_uid", order.OrderUID))
+		default:
+			problem.Write(c, problem.New(c.Request, http.StatusInternalServerError, "failed to store order").
+				With("order_uid", order.OrderUID))
+		}
+	case <-timer.C:
+		// Заказ уже опубликован: publish_status отличает таймаут сохранения от ошибки публикации
+		problem.Write(c, problem.New(c.Request, http.StatusGatewayTimeout, "timeout waiting for order to be stored").
+			With("publish_status", "published").
+			With("order_uid", order.OrderUID))
+	case <-c.Request.Context().Done():
+	}
+}
+
+// parseOrderFilter читает фильтр заказов из query-параметров запроса.
+func parseOrderFilter(c *gin.Context) (domain.OrderFilter, error) {
+	filter := domain.OrderFilter{
+		CustomerID:      c.Query("customer_id"),
+		DeliveryService: c.Query("delivery_service"),
+	}
+	var err error
+	if filter.From, err = parseTimeParam(c.Query("from")); err != nil {
+		return domain.OrderFilter{}, fmt.Errorf("invalid from: %w", err)
+	}
+	if filter.To, err = parseTimeParam(c.Query("to")); err != nil {
+		return domain.OrderFilter{}, fmt.Errorf("invalid to: %w", err)
+	}
+	return filter, nil
+}
+
+// queryInt читает целочисленный query-параметр. Отсутствующий параметр даёт def.
+func queryInt(c *gin.Context, name string, def int) (int, error) {
+	raw, ok := c.GetQuery(name)
+	if !ok {
+		return def, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q is not an integer", name, raw)
+	}
+	return n, nil
+}
+
+// parseTimeParam разбирает время в формате RFC3339 или дату YYYY-MM-DD (UTC).
+// Пустая строка даёт нулевое время.
+func parseTimeParam(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, s)
 }
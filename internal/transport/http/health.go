@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	gin "github.com/gin-gonic/gin"
+
+	"wb-l0-go/internal/transport/problem"
+)
+
+// HealthHandler обслуживает пробы liveness и readiness для оркестратора.
+type HealthHandler struct {
+	// draining сообщает, что экземпляр выводится из работы и трафик на него направлять не нужно
+	draining func() bool
+}
+
+func NewHealthHandler(draining func() bool) *HealthHandler {
+	return &HealthHandler{draining: draining}
+}
+
+// RegisterRoutes регистрирует пробы в корне: они не относятся к версионируемому API
+// и описаны в неверсионируемой документации Swagger (docs/root).
+func (h *HealthHandler) RegisterRoutes(r *gin.Engine) {
+	r.GET("/healthz", h.live)
+	r.GET("/readyz", h.ready)
+}
+
+// live — liveness проба, отвечает 200, пока процесс обслуживает запросы.
+//
+// @Summary      Liveness
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Router       /healthz [get]
+func (h *HealthHandler) live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// ready — readiness проба. Возвращает 503, после того как consumer переведён в режим drain.
+//
+// @Summary      Readiness
+// @Description  Возвращает 503, после того как consumer переведён в режим drain.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      503  {object}  problem.Problem
+// @Router       /readyz [get]
+func (h *HealthHandler) ready(c *gin.Context) {
+	if h.draining != nil && h.draining() {
+		problem.Write(c, problem.New(c.Request, http.StatusServiceUnavailable, "consumer is draining").With("state", "draining"))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
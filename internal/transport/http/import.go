@@ -0,0 +1,130 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/export"
+	"wb-l0-go/internal/importer"
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/service"
+	"wb-l0-go/internal/transport/problem"
+)
+
+// Максимальное количество отклонённых записей в ответе POST /orders/import
+const maxImportRejections = 1000
+
+type importResponse struct {
+	service.ImportResult
+	Rejections []service.ImportRejection `json:"rejections"`
+	// RejectionsTruncated — в ответ попали не все отклонённые записи
+	RejectionsTruncated bool `json:"rejections_truncated"`
+}
+
+// @Summary      Импорт заказов из файла
+// @Description  Загружает заказы из NDJSON или CSV (в формате выгрузки /orders/export). Файл передаётся телом запроса
+// @Description  или полем file multipart-формы. Невалидные записи отклоняются с указанием строки и причины.
+// @Description  Повторный запрос с тем же import_id продолжает прерванный импорт.
+// @Tags         orders
+// @Accept       application/x-ndjson
+// @Accept       text/csv
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        format     query  string  false  "Формат файла: ndjson или csv (по умолчанию определяется по Content-Type)"
+// @Param        import_id  query  string  false  "Идентификатор импорта для продолжения после прерывания"
+// @Param        restart    query  bool    false  "Начать импорт с начала, сбросив сохранённую позицию"
+// @Param        file       formData  file  false  "Файл импорта"
+// @Success      200  {object}  importResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      409  {object}  problem.Problem  "Импорт с тем же import_id уже выполняется"
+// @Failure      500  {object}  problem.Problem  "Результат до ошибки — в поле result"
+// @Router       /orders/import [post]
+func (h *Handler) importOrders(c *gin.Context) {
+	body := io.Reader(c.Request.Body)
+	contentType := c.ContentType()
+	filename := ""
+	if strings.HasPrefix(contentType, "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			problem.Error(c, http.StatusBadRequest, "file is required")
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			problem.Error(c, http.StatusBadRequest, "failed to open file")
+			return
+		}
+		defer f.Close()
+		body, filename = f, fh.Filename
+		contentType = fh.Header.Get("Content-Type")
+	}
+
+	format, err := importFormat(c.Query("format"), contentType, filename)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	restart, _ := strconv.ParseBool(c.DefaultQuery("restart", "false"))
+	importID := c.Query("import_id")
+	if importID == "" {
+		importID = fmt.Sprintf("http-%d", time.Now().UnixNano())
+	}
+
+	reader, err := importer.NewReader(format, body)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp := importResponse{Rejections: []service.ImportRejection{}}
+	res, err := h.importer.Import(c.Request.Context(), reader, service.ImportOptions{
+		ImportID: importID,
+		Restart:  restart,
+		OnReject: func(rej service.ImportRejection) {
+			if len(resp.Rejections) < maxImportRejections {
+				resp.Rejections = append(resp.Rejections, rej)
+			} else {
+				resp.RejectionsTruncated = true
+			}
+		},
+	})
+	resp.ImportResult = res
+	if err != nil {
+		// Результат, достигнутый до ошибки, возвращается в поле result
+		status := problem.Status(err)
+		detail := err.Error()
+		if status == http.StatusInternalServerError {
+			logger.FromContext(c.Request.Context(), h.log).Error("failed to import orders", zap.String("import_id", importID), zap.Error(err))
+			detail = "failed to import orders"
+		}
+		problem.Write(c, problem.New(c.Request, status, detail).With("result", resp))
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// importFormat определяет формат файла импорта по явному параметру, Content-Type или расширению файла.
+func importFormat(param, contentType, filename string) (export.Format, error) {
+	if param != "" {
+		f, err := export.ParseFormat(param)
+		if err != nil || f == export.FormatXLSX {
+			return "", fmt.Errorf("unsupported import format %q", param)
+		}
+		return f, nil
+	}
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil && mt == "text/csv" {
+		return export.FormatCSV, nil
+	}
+	if strings.HasSuffix(strings.ToLower(filename), ".csv") {
+		return export.FormatCSV, nil
+	}
+	return export.FormatNDJSON, nil
+}
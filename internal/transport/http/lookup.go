@@ -0,0 +1,77 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	gin "github.com/gin-gonic/gin"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/transport/problem"
+)
+
+// @Summary      Заказы по трек-номеру
+// @Description  Заказы, у которых трек-номер заказа или одного из товаров совпадает с заданным
+// @Description  (не больше 100, начиная с последних сохранённых).
+// @Tags         orders
+// @Produce      json
+// @Param        track_number  path  string  true  "Трек-номер"
+// @Success      200  {array}   domain.Order
+// @Failure      404  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /tracking/{track_number} [get]
+func (h *Handler) trackOrders(c *gin.Context) {
+	orders, err := h.service.TrackOrders(c.Request.Context(), c.Param("track_number"))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			problem.Error(c, http.StatusNotFound, "no orders with this track number")
+			return
+		}
+		serviceError(c, h.log, "failed to find orders by track number", err)
+		return
+	}
+	c.JSON(http.StatusOK, orders)
+}
+
+// @Summary      Поиск товаров
+// @Description  Товары, совпадающие по всем заданным параметрам, вместе с заказом, в котором они находятся.
+// @Description  Должен быть задан хотя бы один из rid, nm_id и chrt_id.
+// @Tags         orders
+// @Produce      json
+// @Param        rid      query  string  false  "RID товара"
+// @Param        nm_id    query  int     false  "nm_id товара"
+// @Param        chrt_id  query  int     false  "chrt_id товара"
+// @Param        limit    query  int     false  "Limit"  default(50)
+// @Param        offset   query  int     false  "Offset"
+// @Success      200  {array}   domain.ItemMatch
+// @Failure      400  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /items [get]
+func (h *Handler) findItems(c *gin.Context) {
+	q := domain.ItemQuery{Rid: c.Query("rid")}
+	var err error
+	if q.NmID, err = queryInt(c, "nm_id", 0); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if q.ChrtID, err = queryInt(c, "chrt_id", 0); err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	limit, err := queryInt(c, "limit", 50)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	offset, err := queryInt(c, "offset", 0)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	items, err := h.service.FindItems(c.Request.Context(), q, limit, offset)
+	if err != nil {
+		serviceError(c, h.log, "failed to find items", err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/requestid"
+	"wb-l0-go/internal/transport/problem"
+)
+
+// RequestID берёт идентификатор запроса из заголовка X-Request-ID или генерирует новый,
+// кладёт его в контекст запроса вместе с полем request_id для логов (см. logger.FromContext)
+// и возвращает в заголовке ответа.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		ctx := requestid.WithContext(c.Request.Context(), id)
+		ctx = logger.WithFields(ctx, zap.String("request_id", id))
+		c.Request = c.Request.WithContext(ctx)
+		c.Header(requestid.Header, id)
+		c.Next()
+	}
+}
+
+// AccessLog пишет в log строку о каждом обработанном запросе. Подключается после RequestID,
+// чтобы строка содержала request_id.
+func AccessLog(log *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		logger.FromContext(c.Request.Context(), log).Info("http request",
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()))
+	}
+}
+
+// Recovery отвечает 500 в формате problem+json, если обработчик запаниковал.
+func Recovery(log *zap.Logger) gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, err any) {
+		logger.FromContext(c.Request.Context(), log).Error("panic while handling request",
+			zap.String("path", c.Request.URL.Path),
+			zap.Any("panic", err))
+		problem.Error(c, http.StatusInternalServerError, "internal error")
+	})
+}
+
+// NoRoute отвечает 404 в формате problem+json на запросы к неизвестным путям.
+func NoRoute(c *gin.Context) {
+	problem.Error(c, http.StatusNotFound, "route not found")
+}
+
+// serviceError отвечает ошибкой сервиса: доменные ошибки — соответствующим кодом и текстом
+// ошибки, остальные пишутся в лог и возвращаются клиенту как 500 без подробностей.
+func serviceError(c *gin.Context, log *zap.Logger, msg string, err error) {
+	status := problem.Status(err)
+	if status != http.StatusInternalServerError {
+		problem.Error(c, status, err.Error())
+		return
+	}
+	logger.FromContext(c.Request.Context(), log).Error(msg, zap.Error(err))
+	problem.Error(c, status, "internal error")
+}
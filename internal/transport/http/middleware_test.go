@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gin "github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/requestid"
+	"wb-l0-go/internal/service"
+	"wb-l0-go/internal/transport/problem"
+)
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID(), Recovery(zap.NewNop()))
+	r.NoRoute(NoRoute)
+	return r
+}
+
+func TestRequestID(t *testing.T) {
+	r := newTestRouter()
+	var seen string
+	r.GET("/ping", func(c *gin.Context) { seen = requestid.FromContext(c.Request.Context()) })
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(requestid.Header, "client-id-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "client-id-1", seen)
+	assert.Equal(t, "client-id-1", w.Header().Get(requestid.Header))
+
+	// Некорректный идентификатор заменяется сгенерированным
+	req = httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(requestid.Header, "bad id")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.NotEqual(t, "bad id", seen)
+	assert.Len(t, seen, 32)
+	assert.Equal(t, seen, w.Header().Get(requestid.Header))
+}
+
+func TestProblemResponses(t *testing.T) {
+	r := newTestRouter()
+	h := NewHandler(service.NewOrderService(nil, nil, nil, nil, zap.NewNop(), nil, nil), nil, nil, 0, 0, zap.NewNop())
+	r.GET("/orders", h.listOrders)
+	r.GET("/panic", func(*gin.Context) { panic("boom") })
+
+	tests := []struct {
+		path   string
+		status int
+		detail string
+	}{
+		{path: "/orders?limit=abc", status: http.StatusBadRequest, detail: `invalid limit: "abc" is not an integer`},
+		{path: "/orders?offset=-1", status: http.StatusBadRequest, detail: "invalid argument: offset must not be negative"},
+		{path: "/orders?from=2024-05-02&to=2024-05-01", status: http.StatusBadRequest, detail: "invalid argument: from must be before to"},
+		{path: "/panic", status: http.StatusInternalServerError, detail: "internal error"},
+		{path: "/missing", status: http.StatusNotFound, detail: "route not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, tt.status, w.Code)
+			assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+
+			var p problem.Problem
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+			assert.Equal(t, tt.status, p.Status)
+			assert.Equal(t, tt.detail, p.Detail)
+			assert.Equal(t, w.Header().Get(requestid.Header), p.RequestID)
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	gin "github.com/gin-gonic/gin"
+
+	"wb-l0-go/internal/codec"
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/transport/bus"
+	"wb-l0-go/internal/transport/problem"
+)
+
+const (
+	// Максимальное количество заказов в одном запросе пакетной публикации
+	maxBatchOrders = 10000
+	// Максимальный размер тела запроса пакетной публикации
+	maxBatchBodyBytes = 64 << 20
+)
+
+// batchItem — заказ из тела запроса вместе с ошибкой разбора, если она была.
+type batchItem struct {
+	order domain.Order
+	err   error
+}
+
+type batchPublishResult struct {
+	Index    int    `json:"index"`
+	OrderUID string `json:"order_uid,omitempty"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+type batchPublishResponse struct {
+	Accepted int                  `json:"accepted"`
+	Rejected int                  `json:"rejected"`
+	Results  []batchPublishResult `json:"results"`
+}
+
+// @Summary      Пакетная публикация заказов
+// @Description  Опубликовать несколько заказов в Kafka. Принимает JSON массив или NDJSON (application/x-ndjson).
+// @Description  Каждый заказ валидируется отдельно, результат возвращается для каждого заказа.
+// @Description  X-Correlation-ID и X-Source-System передаются в заголовках всех сообщений пачки.
+// @Tags         orders
+// @Accept       json
+// @Accept       application/x-ndjson
+// @Produce      json
+// @Param        orders body []domain.Order true "Orders"
+// @Param        X-Correlation-ID  header  string  false  "ID для сквозной трассировки заказов"
+// @Param        X-Source-System   header  string  false  "Система, публикующая заказы"
+// @Success      202  {object}  batchPublishResponse
+// @Failure      400  {object}  problem.Problem
+// @Failure      413  {object}  problem.Problem
+// @Failure      503  {object}  problem.Problem
+// @Router       /publish/batch [post]
+func (h *Handler) publishBatch(c *gin.Context) {
+	if h.producer == nil {
+		problem.Error(c, http.StatusServiceUnavailable, "producer not initialized")
+		return
+	}
+	headers, err := publishHeaders(c.Request, codec.ContentTypeJSON)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	c.Header(correlationIDHeader, headers[domain.HeaderCorrelationID])
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxBatchBodyBytes)
+	items, err := decodeBatch(body, c.ContentType())
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			problem.Error(c, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(items) == 0 {
+		problem.Error(c, http.StatusBadRequest, "no orders in request")
+		return
+	}
+
+	resp := batchPublishResponse{Results: make([]batchPublishResult, len(items))}
+	msgs := make([]bus.Message, 0, len(items))
+	// msgIdx[i] — индекс заказа в запросе для i-го сообщения
+	msgIdx := make([]int, 0, len(items))
+
+	for i, item := range items {
+		res := batchPublishResult{Index: i, OrderUID: item.order.OrderUID}
+		if item.err != nil {
+			res.Status, res.Error = "rejected", item.err.Error()
+			resp.Results[i] = res
+			continue
+		}
+		if err := h.service.Validate(item.order); err != nil {
+			res.Status, res.Error = "rejected", err.Error()
+			resp.Results[i] = res
+			continue
+		}
+		item.order.Headers = nil
+		payload, err := json.Marshal(item.order)
+		if err != nil {
+			res.Status, res.Error = "rejected", "failed to marshal order"
+			resp.Results[i] = res
+			continue
+		}
+		resp.Results[i] = res
+		msgs = append(msgs, bus.Message{Key: item.order.OrderUID, Value: payload, Headers: headers})
+		msgIdx = append(msgIdx, i)
+	}
+
+	errs := h.producer.PublishBatch(c.Request.Context(), msgs)
+	for j, err := range errs {
+		res := &resp.Results[msgIdx[j]]
+		if err != nil {
+			res.Status, res.Error = "rejected", "failed to publish"
+			continue
+		}
+		res.Status = "accepted"
+	}
+
+	for _, res := range resp.Results {
+		if res.Status == "accepted" {
+			resp.Accepted++
+		} else {
+			resp.Rejected++
+		}
+	}
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// decodeBatch разбирает тело запроса как JSON массив заказов или как NDJSON.
+// Формат определяется по Content-Type, а при его отсутствии — по первому непробельному символу.
+// Ошибки разбора отдельных заказов не прерывают обработку и возвращаются в batchItem.
+func decodeBatch(r io.Reader, contentType string) ([]batchItem, error) {
+	br := bufio.NewReader(r)
+
+	ndjson := false
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mt {
+		case "application/x-ndjson", "application/ndjson", "application/jsonl":
+			ndjson = true
+		}
+	}
+	if !ndjson {
+		first, err := peekNonSpace(br)
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ndjson = first != '['
+	}
+
+	if ndjson {
+		return decodeNDJSON(br)
+	}
+	return decodeJSONArray(br)
+}
+
+func decodeJSONArray(r io.Reader) ([]batchItem, error) {
+	var raws []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raws); err != nil {
+		return nil, fmt.Errorf("invalid JSON array: %w", err)
+	}
+	if len(raws) > maxBatchOrders {
+		return nil, fmt.Errorf("too many orders: %d, max %d", len(raws), maxBatchOrders)
+	}
+	items := make([]batchItem, len(raws))
+	for i, raw := range raws {
+		if err := json.Unmarshal(raw, &items[i].order); err != nil {
+			items[i].err = fmt.Errorf("invalid JSON: %w", err)
+		}
+	}
+	return items, nil
+}
+
+func decodeNDJSON(r io.Reader) ([]batchItem, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxBatchBodyBytes)
+
+	var items []batchItem
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if len(items) == maxBatchOrders {
+			return nil, fmt.Errorf("too many orders, max %d", maxBatchOrders)
+		}
+		var item batchItem
+		if err := json.Unmarshal(line, &item.order); err != nil {
+			item.err = fmt.Errorf("invalid JSON: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// peekNonSpace пропускает пробельные символы и возвращает первый значимый байт, не вычитывая его.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBatch_JSONArray(t *testing.T) {
+	body := `  [{"order_uid":"a"}, {"order_uid":"b"}, {"order_uid": 1}]`
+
+	items, err := decodeBatch(strings.NewReader(body), "application/json")
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	assert.Equal(t, "a", items[0].order.OrderUID)
+	assert.NoError(t, items[0].err)
+	assert.Equal(t, "b", items[1].order.OrderUID)
+	// Ошибка типа в одном заказе не ломает весь запрос
+	assert.Error(t, items[2].err)
+}
+
+func TestDecodeBatch_NDJSON(t *testing.T) {
+	body := "{\"order_uid\":\"a\"}\n\n{not json}\n{\"order_uid\":\"c\"}\n"
+
+	items, err := decodeBatch(strings.NewReader(body), "application/x-ndjson")
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	assert.Equal(t, "a", items[0].order.OrderUID)
+	assert.Error(t, items[1].err)
+	assert.Equal(t, "c", items[2].order.OrderUID)
+}
+
+func TestDecodeBatch_DetectsNDJSONWithoutContentType(t *testing.T) {
+	body := "{\"order_uid\":\"a\"}\n{\"order_uid\":\"b\"}"
+
+	items, err := decodeBatch(strings.NewReader(body), "")
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+}
+
+func TestDecodeBatch_InvalidArray(t *testing.T) {
+	_, err := decodeBatch(strings.NewReader(`[{"order_uid":"a"`), "application/json")
+	assert.Error(t, err)
+}
@@ -0,0 +1,31 @@
+package http
+
+import (
+	"net/http"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/requestid"
+	"wb-l0-go/internal/transport/bus"
+)
+
+// HTTP заголовки запроса публикации, передаваемые в заголовки сообщения
+const (
+	correlationIDHeader = "X-Correlation-ID"
+	sourceSystemHeader  = "X-Source-System"
+)
+
+// publishHeaders собирает заголовки сообщения из заголовков запроса публикации.
+// contentType — формат тела сообщения: для POST /publish это Content-Type запроса,
+// для пакетной публикации — JSON, в котором публикуется каждый заказ.
+// Если X-Correlation-ID не задан, генерируется новый ID. ID запроса передаётся
+// в заголовке request-id, чтобы логи consumer'а можно было связать с логами публикации.
+func publishHeaders(req *http.Request, contentType string) (map[string]string, error) {
+	headers, err := bus.OrderHeaders(contentType, req.Header.Get(correlationIDHeader), req.Header.Get(sourceSystemHeader))
+	if err != nil {
+		return nil, err
+	}
+	if id := requestid.FromContext(req.Context()); id != "" {
+		headers[domain.HeaderRequestID] = id
+	}
+	return headers, nil
+}
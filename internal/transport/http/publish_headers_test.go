@@ -0,0 +1,136 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/codec"
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/requestid"
+	"wb-l0-go/internal/service"
+	"wb-l0-go/internal/transport/bus"
+	"wb-l0-go/internal/transport/problem"
+)
+
+func newPublishRequest() *http.Request {
+	return httptest.NewRequest(http.MethodPost, "/publish", nil)
+}
+
+func TestPublishHeaders(t *testing.T) {
+	req := newPublishRequest()
+	req.Header.Set(correlationIDHeader, "corr-1")
+	req.Header.Set(sourceSystemHeader, "crm")
+	req = req.WithContext(requestid.WithContext(req.Context(), "req-1"))
+
+	headers, err := publishHeaders(req, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		domain.HeaderCorrelationID: "corr-1",
+		domain.HeaderSourceSystem:  "crm",
+		domain.HeaderContentType:   "application/json",
+		domain.HeaderRequestID:     "req-1",
+	}, headers)
+}
+
+func TestPublishHeaders_ContentType(t *testing.T) {
+	headers, err := publishHeaders(newPublishRequest(), codec.ContentTypeMsgPack)
+	require.NoError(t, err)
+	assert.Equal(t, codec.ContentTypeMsgPack, headers[domain.HeaderContentType])
+}
+
+func TestPublishHeaders_GeneratesCorrelationID(t *testing.T) {
+	first, err := publishHeaders(newPublishRequest(), "")
+	require.NoError(t, err)
+	second, err := publishHeaders(newPublishRequest(), "")
+	require.NoError(t, err)
+
+	assert.Len(t, first[domain.HeaderCorrelationID], 32)
+	assert.NotEqual(t, first[domain.HeaderCorrelationID], second[domain.HeaderCorrelationID])
+	assert.NotContains(t, first, domain.HeaderSourceSystem)
+	assert.NotContains(t, first, domain.HeaderRequestID)
+}
+
+func TestPublishHeaders_TooLong(t *testing.T) {
+	req := newPublishRequest()
+	req.Header.Set(sourceSystemHeader, strings.Repeat("a", bus.MaxHeaderValueLen+1))
+
+	_, err := publishHeaders(req, "")
+	assert.Error(t, err)
+}
+
+func TestPublish_ContentType(t *testing.T) {
+	memBus := bus.NewMemory(10)
+	svc := service.NewOrderService(nil, nil, nil, nil, zap.NewNop(), nil, nil)
+	r := newTestRouter()
+	h := NewHandler(svc, nil, memBus, 0, 0, zap.NewNop())
+	r.POST("/publish", h.publish)
+
+	order := domain.Order{
+		OrderUID: "order-1", TrackNumber: "WBILMTESTTRACK", Entry: "WBIL", Locale: "en", CustomerID: "test",
+		DeliveryService: "meest", ShardKey: "9", SmID: 99, OofShard: "1",
+		DateCreated: time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		Delivery: domain.Delivery{
+			Name: "Test Testov", Phone: "+9720000000", Zip: "2639809", City: "Kiryat Mozkin",
+			Address: "Ploshad Mira 15", Region: "Kraiot", Email: "test@gmail.com",
+		},
+		Payment: domain.Payment{
+			Transaction: "order-1", Currency: "USD", Provider: "wbpay", Amount: 1817, PaymentDt: 1637907727,
+			Bank: "alpha", DeliveryCost: 1500, GoodsTotal: 317,
+		},
+		Items: []domain.Items{{
+			ChrtID: 9934930, TrackNumber: "WBILMTESTTRACK", Price: 453, Rid: "ab4219087a764ae0btest",
+			Name: "Mascaras", TotalPrice: 317, NmID: 2389212, Brand: "Vivienne Sabo", Status: 202,
+		}},
+	}
+	msgpack, err := svc.EncodeOrder(codec.ContentTypeMsgPack, order)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/publish", bytes.NewReader(msgpack))
+	req.Header.Set("Content-Type", codec.ContentTypeMsgPack)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+	// Тело публикуется без изменений и разбирается обработчиком сообщений по content-type
+	handled := make(chan domain.Order, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = memBus.Subscriber(bus.HandlerFunc(func(_ context.Context, env domain.Envelope) error {
+			assert.Equal(t, msgpack, env.Payload)
+			decoded, err := svc.CheckOrder(env)
+			assert.NoError(t, err)
+			handled <- decoded
+			return err
+		}), zap.NewNop()).Run(ctx)
+	}()
+	select {
+	case decoded := <-handled:
+		assert.Equal(t, "order-1", decoded.OrderUID)
+		assert.Equal(t, order.Payment, decoded.Payment)
+		assert.Equal(t, codec.ContentTypeMsgPack, decoded.Headers[domain.HeaderContentType])
+	case <-time.After(time.Second):
+		t.Fatal("order was not handled")
+	}
+
+	// Формат без кодека отклоняется до публикации
+	req = httptest.NewRequest(http.MethodPost, "/publish", strings.NewReader("order"))
+	req.Header.Set("Content-Type", "text/plain")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	assert.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	assert.Equal(t, http.StatusUnsupportedMediaType, p.Status)
+}
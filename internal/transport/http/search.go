@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+
+	gin "github.com/gin-gonic/gin"
+
+	"wb-l0-go/internal/transport/problem"
+)
+
+// @Summary      Полнотекстовый поиск заказов
+// @Description  Поиск по имени получателя, городу, адресу, названиям и брендам товаров. Запрос поддерживает
+// @Description  синтаксис websearch_to_tsquery: фразы в кавычках, "-" для исключения слова, "or" для альтернатив.
+// @Description  Результаты упорядочены по релевантности, highlight содержит фрагменты с найденными словами
+// @Description  в <mark></mark> (остальной текст экранирован как HTML).
+// @Tags         orders
+// @Produce      json
+// @Param        q       query  string  true   "Поисковый запрос"
+// @Param        limit   query  int     false  "Limit"  default(50)
+// @Param        offset  query  int     false  "Offset"
+// @Success      200  {object}  domain.SearchPage
+// @Failure      400  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /orders/search [get]
+func (h *Handler) searchOrders(c *gin.Context) {
+	limit, err := queryInt(c, "limit", 50)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	offset, err := queryInt(c, "offset", 0)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, err := h.service.SearchOrders(c.Request.Context(), c.Query("q"), limit, offset)
+	if err != nil {
+		serviceError(c, h.log, "failed to search orders", err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+
+	gin "github.com/gin-gonic/gin"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/transport/problem"
+)
+
+// @Summary      Статистика заказов
+// @Description  Количество заказов и выручка (сумма payment.amount) по интервалам date_created в UTC.
+// @Description  group_by разбивает каждый интервал по значениям поля заказа. При разбивке по brand заказ
+// @Description  учитывается в каждом бренде своих товаров, а выручкой считается сумма total_price товаров бренда.
+// @Description  Выручка суммируется без пересчёта валют, для раздельного учёта используйте group_by=currency.
+// @Description  По умолчанию период — последние 30 дней.
+// @Tags         stats
+// @Produce      json
+// @Param        interval          query  string  false  "Размер интервала: hour, day или week (неделя с понедельника)"  default(day)
+// @Param        group_by          query  string  false  "Разбивка: delivery_service, provider, bank, currency, region или brand"
+// @Param        customer_id       query  string  false  "Фильтр по customer_id"
+// @Param        delivery_service  query  string  false  "Фильтр по delivery_service"
+// @Param        from              query  string  false  "date_created не раньше (RFC3339 или YYYY-MM-DD)"
+// @Param        to                query  string  false  "date_created раньше (RFC3339 или YYYY-MM-DD), по умолчанию — текущее время"
+// @Success      200  {object}  domain.OrderStats
+// @Failure      400  {object}  problem.Problem
+// @Failure      500  {object}  problem.Problem
+// @Router       /stats/orders [get]
+func (h *Handler) orderStats(c *gin.Context) {
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	stats, err := h.service.OrderStats(c.Request.Context(), domain.StatsQuery{
+		Filter:   filter,
+		Interval: domain.StatsInterval(c.Query("interval")),
+		GroupBy:  domain.StatsGroup(c.Query("group_by")),
+	})
+	if err != nil {
+		serviceError(c, h.log, "failed to get order stats", err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
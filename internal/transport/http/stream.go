@@ -0,0 +1,215 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	gin "github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/feed"
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/transport/problem"
+)
+
+// Время на запись одного сообщения в WebSocket
+const wsWriteWait = 10 * time.Second
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// feedFilter — фильтр ленты заказов по query-параметрам запроса.
+type feedFilter struct {
+	customerID      string
+	deliveryService string
+}
+
+func newFeedFilter(c *gin.Context) feedFilter {
+	return feedFilter{
+		customerID:      c.Query("customer_id"),
+		deliveryService: c.Query("delivery_service"),
+	}
+}
+
+func (f feedFilter) match(order domain.Order) bool {
+	if f.customerID != "" && order.CustomerID != f.customerID {
+		return false
+	}
+	if f.deliveryService != "" && order.DeliveryService != f.deliveryService {
+		return false
+	}
+	return true
+}
+
+// lastEventID возвращает ID последнего полученного клиентом события из заголовка
+// Last-Event-ID или параметра last_event_id (EventSource не умеет задавать заголовки
+// при первом подключении).
+func lastEventID(c *gin.Context) (uint64, error) {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
+
+// @Summary      Лента новых заказов (SSE)
+// @Description  Server-Sent Events поток заказов, сохранённых consumer'ом. Каждое событие содержит заказ в data
+// @Description  и его ID в поле id. Для продолжения после обрыва передайте Last-Event-ID или last_event_id.
+// @Tags         orders
+// @Produce      text/event-stream
+// @Param        customer_id       query  string  false  "Фильтр по customer_id"
+// @Param        delivery_service  query  string  false  "Фильтр по delivery_service"
+// @Param        last_event_id     query  int     false  "ID последнего полученного события"
+// @Param        Last-Event-ID     header int     false  "ID последнего полученного события"
+// @Success      200  {object}  domain.Order
+// @Failure      400  {object}  problem.Problem
+// @Router       /orders/stream [get]
+func (h *Handler) streamOrders(c *gin.Context) {
+	filter := newFeedFilter(c)
+	lastID, err := lastEventID(c)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, "invalid last event id")
+		return
+	}
+
+	backlog, events, cancel := h.service.SubscribeOrders(lastID)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Отключаем буферизацию ответа в nginx
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	writeEvent := func(ev feed.Event) {
+		if !filter.match(ev.Order) {
+			return
+		}
+		c.Render(-1, sse.Event{Id: strconv.FormatUint(ev.ID, 10), Event: "order", Data: ev.Order})
+	}
+
+	for _, ev := range backlog {
+		writeEvent(ev)
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-h.done:
+			return
+		case ev, ok := <-events:
+			if !ok {
+				// Клиент не успевал читать события и был отключён, он переподключится с Last-Event-ID
+				return
+			}
+			writeEvent(ev)
+			c.Writer.Flush()
+		case <-ticker.C:
+			fmt.Fprint(c.Writer, ": heartbeat\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// @Summary      Лента новых заказов (WebSocket)
+// @Description  WebSocket поток заказов, сохранённых consumer'ом. Каждое сообщение — JSON объект с полями id, time и order.
+// @Description  Для продолжения после обрыва передайте last_event_id.
+// @Tags         orders
+// @Param        customer_id       query  string  false  "Фильтр по customer_id"
+// @Param        delivery_service  query  string  false  "Фильтр по delivery_service"
+// @Param        last_event_id     query  int     false  "ID последнего полученного события"
+// @Success      101  {object}  feed.Event
+// @Failure      400  {object}  problem.Problem
+// @Router       /orders/ws [get]
+func (h *Handler) streamOrdersWS(c *gin.Context) {
+	filter := newFeedFilter(c)
+	lastID, err := lastEventID(c)
+	if err != nil {
+		problem.Error(c, http.StatusBadRequest, "invalid last event id")
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade уже отправил клиенту ответ с ошибкой
+		logger.FromContext(c.Request.Context(), h.log).Debug("websocket upgrade failed", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	backlog, events, cancel := h.service.SubscribeOrders(lastID)
+	defer cancel()
+
+	// Читаем входящие сообщения, чтобы обрабатывать pong и закрытие соединения клиентом
+	pongWait := 2 * h.heartbeat
+	_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	writeEvent := func(ev feed.Event) error {
+		if !filter.match(ev.Order) {
+			return nil
+		}
+		_ = conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		return conn.WriteJSON(ev)
+	}
+
+	for _, ev := range backlog {
+		if err := writeEvent(ev); err != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-h.done:
+			_ = conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+				time.Now().Add(wsWriteWait))
+			return
+		case ev, ok := <-events:
+			if !ok {
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber too slow"),
+					time.Now().Add(wsWriteWait))
+				return
+			}
+			if err := writeEvent(ev); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
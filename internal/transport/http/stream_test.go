@@ -0,0 +1,68 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/feed"
+	"wb-l0-go/internal/service"
+)
+
+func newStreamServer(t *testing.T) (*Handler, *httptest.Server) {
+	t.Helper()
+	r := newTestRouter()
+	svc := service.NewOrderService(nil, nil, nil, nil, zap.NewNop(), nil, feed.New(10))
+	h := NewHandler(svc, nil, nil, time.Minute, 0, zap.NewNop())
+	r.GET("/orders/stream", h.streamOrders)
+	r.GET("/orders/ws", h.streamOrdersWS)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return h, srv
+}
+
+func TestStreamOrders_ClosedOnShutdown(t *testing.T) {
+	h, srv := newStreamServer(t)
+
+	resp, err := http.Get(srv.URL + "/orders/stream")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	h.CloseStreams()
+
+	// Поток должен завершиться, не дожидаясь отключения клиента
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.Discard, resp.Body)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("sse stream was not closed")
+	}
+}
+
+func TestStreamOrdersWS_ClosedOnShutdown(t *testing.T) {
+	h, srv := newStreamServer(t)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/orders/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	h.CloseStreams()
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected error: %v", err)
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+)
+
+// APIv1 — префикс первой версии REST API
+const APIv1 = "/api/v1"
+
+// legacyDeprecatedAt — дата, с которой пути без префикса версии считаются устаревшими (выход /api/v1)
+var legacyDeprecatedAt = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+// Routes регистрирует маршруты REST API относительно группы.
+type Routes interface {
+	RegisterRoutes(r gin.IRouter)
+}
+
+// RegisterAPI регистрирует маршруты handlers под /api/v1 и, для обратной совместимости, без префикса.
+// Ответы на старые пути содержат заголовки Deprecation, Link на путь в /api/v1 и, если sunset
+// не нулевой, Sunset с датой отключения.
+func RegisterAPI(r *gin.Engine, sunset time.Time, handlers ...Routes) {
+	v1 := r.Group(APIv1)
+	legacy := r.Group("/", Deprecated(APIv1, legacyDeprecatedAt, sunset))
+	for _, h := range handlers {
+		h.RegisterRoutes(v1)
+		h.RegisterRoutes(legacy)
+	}
+}
+
+// Deprecated помечает ответы устаревшего маршрута заголовками Deprecation (RFC 9745), Sunset (RFC 8594)
+// и Link с rel="successor-version" на тот же путь с префиксом successor. Заголовки выставляются
+// до вызова обработчика, поэтому попадают и в потоковые ответы.
+func Deprecated(successor string, deprecatedAt, sunset time.Time) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", deprecatedAt.Unix())
+	var sunsetHeader string
+	if !sunset.IsZero() {
+		sunsetHeader = sunset.UTC().Format(http.TimeFormat)
+	}
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		if sunsetHeader != "" {
+			c.Header("Sunset", sunsetHeader)
+		}
+		c.Header("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successor, c.Request.URL.RequestURI()))
+		c.Next()
+	}
+}
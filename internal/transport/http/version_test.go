@@ -0,0 +1,77 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gin "github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type pingRoutes struct{}
+
+func (pingRoutes) RegisterRoutes(r gin.IRouter) {
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+}
+
+func TestRegisterAPI(t *testing.T) {
+	r := newTestRouter()
+	sunset := time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+	RegisterAPI(r, sunset, pingRoutes{})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+
+	// Старый путь работает, но помечен устаревшим
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping?x=1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "pong", w.Body.String())
+	assert.Regexp(t, `^@\d+$`, w.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/ping?x=1>; rel="successor-version"`, w.Header().Get("Link"))
+
+	// Без даты отключения Sunset не выставляется
+	r = newTestRouter()
+	RegisterAPI(r, time.Time{}, pingRoutes{})
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.NotEmpty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+}
+
+func TestRegisterAPI_AllRoutes(t *testing.T) {
+	// Маршруты обработчиков не конфликтуют с UI и между версиями
+	r := newTestRouter()
+	h := NewHandler(nil, nil, nil, 0, 0, zap.NewNop())
+	assert.NotPanics(t, func() {
+		h.RegisterUI(r)
+		RegisterAPI(r, time.Time{}, h, NewAdminHandler(nil, nil, nil, zap.NewNop()))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger/", nil))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/swagger/v1/index.html", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger/v1/doc.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"basePath": "/api/v1"`)
+	assert.NotContains(t, w.Body.String(), `"/healthz"`)
+
+	// GraphQL и пробы описаны в отдельной неверсионируемой документации
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swagger/root/doc.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"basePath": "/"`)
+	assert.Contains(t, w.Body.String(), `"/healthz"`)
+	assert.Contains(t, w.Body.String(), `"/readyz"`)
+	assert.Contains(t, w.Body.String(), `"/graphql"`)
+}
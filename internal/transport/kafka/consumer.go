@@ -2,56 +2,187 @@ package kafka
 
 import (
 	"context"
-	"time"
+	"fmt"
+	"os"
+	"sync"
 
 	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
 
-	"wb-l0-go/internal/service"
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/transport/bus"
 )
 
+// Consumer читает топик заказов в составе группы. Реализует bus.Subscriber.
 type Consumer struct {
-	reader *kafka.Reader
-	svc    *service.OrderService
-	log    *zap.Logger
+	reader  *kafka.Reader
+	svc     bus.OrderHandler
+	dlq     bus.Publisher
+	log     *zap.Logger
+	brokers []string
+	topic   string
+	groupID string
+	// clientID уникален для экземпляра приложения и позволяет найти его участника в описании группы
+	clientID string
+	sec      *Security
+
+	mu     sync.Mutex
+	paused bool
+	// drained — consumer вышел из группы в режиме drain, возобновить чтение нельзя
+	drained bool
+	// wake закрывается и пересоздаётся при каждой паузе, возобновлении и drain
+	wake  chan struct{}
+	stats consumerStats
+
+	// inFlight удерживается Run на время чтения, обработки и коммита сообщения
+	inFlight sync.Mutex
 }
 
-func NewConsumer(brokers []string, topic, groupID string, svc *service.OrderService, log *zap.Logger) *Consumer {
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:     brokers,
-		Topic:       topic,
-		GroupID:     groupID,
-		StartOffset: kafka.LastOffset,
-		MinBytes:    1,
-		MaxBytes:    10e6,
-		MaxWait:     500 * time.Millisecond,
-	})
-	return &Consumer{reader: reader, svc: svc, log: log}
+// NewConsumer создаёт consumer группы groupID. Если стартовая позиция задана временем,
+// до запуска reader'а группе выставляются offset'ы по этому времени, поэтому нужен доступ к брокерам.
+func NewConsumer(ctx context.Context, brokers []string, topic, groupID string, opts ConsumerOptions, sec *Security, svc bus.OrderHandler, log *zap.Logger) (*Consumer, error) {
+	cfg, startTime, err := opts.readerConfig(brokers, topic, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if startTime != nil {
+		if err := seedGroupOffsets(ctx, sec, brokers, topic, groupID, *startTime, log); err != nil {
+			return nil, err
+		}
+	}
+	hostname, _ := os.Hostname()
+	clientID := fmt.Sprintf("wb-l0-go-%s-%d", hostname, os.Getpid())
+	dialer := *sec.getDialer()
+	dialer.ClientID = clientID
+
+	cfg.Dialer = &dialer
+	cfg.Logger = readerLogger(log.With(zap.String("group_id", groupID)))
+	cfg.ErrorLogger = readerErrorLogger(log.With(zap.String("group_id", groupID)))
+	return &Consumer{
+		reader:   kafka.NewReader(cfg),
+		svc:      svc,
+		dlq:      opts.DLQ,
+		log:      log,
+		brokers:  brokers,
+		topic:    topic,
+		groupID:  groupID,
+		clientID: clientID,
+		sec:      sec,
+		wake:     make(chan struct{}),
+		stats:    consumerStats{partitions: map[int]*PartitionStatus{}},
+	}, nil
 }
 
 func (c *Consumer) Run(ctx context.Context) error {
 	for {
+		paused, wake := c.state()
+		if paused {
+			// На паузе reader остаётся в группе (heartbeat'ы идут в фоне), но сообщения не читаются
+			select {
+			case <-ctx.Done():
+				c.log.Info("context done, stopping consumer")
+				return ctx.Err()
+			case <-wake:
+				continue
+			}
+		}
+
 		select {
 		case <-ctx.Done():
 			c.log.Info("context done, stopping consumer")
 			return ctx.Err()
 		default:
-			m, err := c.reader.FetchMessage(ctx)
-			if err != nil {
+			if err := c.processNext(ctx, wake); err != nil {
 				return err
 			}
-			if err := c.svc.HandleKafkaOrder(ctx, string(m.Key), m.Value); err != nil {
-				c.log.Error("failed to handle message", zap.Error(err))
-				// не коммитил сообщение, для повторной попытки обработки
-				// continue
-			}
-			if err := c.reader.CommitMessages(ctx, m); err != nil {
-				c.log.Error("failed to commit message", zap.Error(err))
-			}
 		}
 	}
 }
 
+// processNext читает, обрабатывает и коммитит одно сообщение. Ожидание сообщения прерывается
+// при закрытии wake (пауза или drain), в этом случае возвращается nil. Если сообщение
+// не удалось ни обработать, ни отправить в DLQ, оно не коммитится и возвращается ошибка:
+// после перезапуска сообщение будет прочитано снова.
+func (c *Consumer) processNext(ctx context.Context, wake <-chan struct{}) error {
+	c.inFlight.Lock()
+	defer c.inFlight.Unlock()
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-wake:
+			cancel()
+		case <-fetchCtx.Done():
+		}
+	}()
+
+	m, err := c.reader.FetchMessage(fetchCtx)
+	if err != nil {
+		if fetchCtx.Err() != nil && ctx.Err() == nil {
+			return nil
+		}
+		return err
+	}
+	handleErr, err := c.handle(ctx, m)
+	if err != nil {
+		return err
+	}
+	log := logger.FromContext(bus.MessageContext(ctx, envelope(m)), c.log)
+	commitErr := c.reader.CommitMessages(ctx, m)
+	if commitErr != nil {
+		log.Error("failed to commit message", zap.Error(commitErr))
+	}
+	c.record(m, handleErr, commitErr)
+	return nil
+}
+
+// handle передаёт сообщение обработчику. Сообщение, которое не удалось обработать, отправляется
+// в DLQ; handleErr — ошибка обработки, err — ошибка отправки в DLQ.
+func (c *Consumer) handle(ctx context.Context, m kafka.Message) (handleErr, err error) {
+	env := envelope(m)
+	msgCtx := bus.MessageContext(ctx, env)
+	log := logger.FromContext(msgCtx, c.log)
+	handleErr = c.svc.HandleOrder(msgCtx, env)
+	if handleErr == nil {
+		return nil, nil
+	}
+	log.Error("failed to handle message", zap.Int("partition", m.Partition), zap.Int64("offset", m.Offset), zap.Error(handleErr))
+	if c.dlq == nil {
+		return handleErr, nil
+	}
+	if err := c.dlq.Publish(ctx, dlqMessage(m, handleErr)); err != nil {
+		log.Error("failed to publish message to dlq", zap.Int("partition", m.Partition), zap.Int64("offset", m.Offset), zap.Error(err))
+		return handleErr, fmt.Errorf("failed to publish message to dlq: %w", err)
+	}
+	return handleErr, nil
+}
+
 func (c *Consumer) Close() error {
 	return c.reader.Close()
 }
+
+// record учитывает обработанное сообщение в счётчиках consumer'а.
+func (c *Consumer) record(m kafka.Message, handleErr, commitErr error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.stats.partitions[m.Partition]
+	if !ok {
+		p = &PartitionStatus{Partition: m.Partition, Offset: -1, CommittedOffset: -1}
+		c.stats.partitions[m.Partition] = p
+	}
+	p.Offset = m.Offset
+	p.HighWatermark = m.HighWaterMark
+	lastMessage := m.Time
+	p.LastMessageTime = &lastMessage
+	p.Processed++
+	c.stats.processed++
+	if handleErr != nil {
+		p.Errors++
+		c.stats.handleErrors++
+	}
+	if commitErr != nil {
+		c.stats.commitErrors++
+	}
+}
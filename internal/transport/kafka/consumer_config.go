@@ -0,0 +1,234 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/transport/bus"
+)
+
+// Значения ConsumerOptions.StartOffset
+const (
+	StartOffsetEarliest = "earliest"
+	StartOffsetLatest   = "latest"
+)
+
+// ConsumerOptions — настройки reader'а consumer'а. Нулевые значения заменяются значениями
+// по умолчанию kafka-go.
+type ConsumerOptions struct {
+	// StartOffset — откуда читать партиции, для которых у группы нет закоммиченного offset'а:
+	// earliest, latest или время в формате RFC3339 (первое сообщение, записанное не раньше него)
+	StartOffset string
+	MinBytes    int
+	MaxBytes    int
+	MaxWait     time.Duration
+	// CommitInterval — периодичность асинхронного коммита offset'ов, 0 — синхронный коммит
+	// после каждого сообщения
+	CommitInterval time.Duration
+	// Balancers — стратегии распределения партиций в порядке приоритета: range, round-robin
+	Balancers         []string
+	SessionTimeout    time.Duration
+	RebalanceTimeout  time.Duration
+	HeartbeatInterval time.Duration
+	// WatchPartitions — перераспределять партиции при добавлении новых партиций в топик
+	WatchPartitions bool
+	// DLQ публикует сообщения, которые не удалось обработать, перед их коммитом. Если nil,
+	// такие сообщения только логируются и коммитятся
+	DLQ bus.Publisher
+}
+
+// startOffset разбирает StartOffset. Для стартовой позиции по времени возвращает
+// kafka.FirstOffset и само время: смещения по времени выставляются группе до запуска reader'а.
+func (o ConsumerOptions) startOffset() (int64, *time.Time, error) {
+	switch strings.ToLower(strings.TrimSpace(o.StartOffset)) {
+	case "", StartOffsetLatest:
+		return kafka.LastOffset, nil, nil
+	case StartOffsetEarliest:
+		return kafka.FirstOffset, nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, o.StartOffset)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid start offset %q: expected earliest, latest or RFC3339 time", o.StartOffset)
+	}
+	return kafka.FirstOffset, &t, nil
+}
+
+func (o ConsumerOptions) balancers() ([]kafka.GroupBalancer, error) {
+	var balancers []kafka.GroupBalancer
+	for _, name := range o.Balancers {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "":
+		case "range":
+			balancers = append(balancers, kafka.RangeGroupBalancer{})
+		case "round-robin", "roundrobin":
+			balancers = append(balancers, kafka.RoundRobinGroupBalancer{})
+		default:
+			return nil, fmt.Errorf("unknown group balancer %q", name)
+		}
+	}
+	return balancers, nil
+}
+
+// readerConfig собирает конфигурацию kafka.Reader из опций.
+func (o ConsumerOptions) readerConfig(brokers []string, topic, groupID string) (kafka.ReaderConfig, *time.Time, error) {
+	start, startTime, err := o.startOffset()
+	if err != nil {
+		return kafka.ReaderConfig{}, nil, err
+	}
+	balancers, err := o.balancers()
+	if err != nil {
+		return kafka.ReaderConfig{}, nil, err
+	}
+	return kafka.ReaderConfig{
+		Brokers:           brokers,
+		Topic:             topic,
+		GroupID:           groupID,
+		StartOffset:       start,
+		MinBytes:          o.MinBytes,
+		MaxBytes:          o.MaxBytes,
+		MaxWait:           o.MaxWait,
+		CommitInterval:    o.CommitInterval,
+		GroupBalancers:    balancers,
+		SessionTimeout:    o.SessionTimeout,
+		RebalanceTimeout:  o.RebalanceTimeout,
+		HeartbeatInterval: o.HeartbeatInterval,
+
+		WatchPartitionChanges: o.WatchPartitions,
+	}, startTime, nil
+}
+
+// Фрагменты сообщений kafka-go о событиях группы: вступление в группу, назначение партиций,
+// выбор лидера и перебалансировка
+var rebalanceEvents = []string{
+	"joined group",
+	"assigned member",
+	"selected as leader",
+	"received empty assignments",
+	"rebalancing group",
+	"subscribed to topics and partitions",
+}
+
+// readerLogger направляет логи kafka-go в zap: события перебалансировки группы пишутся
+// на уровне info, остальное — на уровне debug.
+func readerLogger(log *zap.Logger) kafka.LoggerFunc {
+	return func(msg string, args ...any) {
+		text := strings.TrimSpace(fmt.Sprintf(msg, args...))
+		for _, event := range rebalanceEvents {
+			if strings.Contains(text, event) {
+				log.Info("consumer group event", zap.String("event", text))
+				return
+			}
+		}
+		log.Debug(text)
+	}
+}
+
+func readerErrorLogger(log *zap.Logger) kafka.LoggerFunc {
+	return func(msg string, args ...any) {
+		log.Error("kafka reader error", zap.String("error", strings.TrimSpace(fmt.Sprintf(msg, args...))))
+	}
+}
+
+// seedGroupOffsets выставляет группе offset'ы по времени t для партиций, по которым у неё ещё
+// нет закоммиченных offset'ов. Уже закоммиченные offset'ы не меняются, поэтому при
+// перезапуске consumer продолжает с места остановки.
+func seedGroupOffsets(ctx context.Context, sec *Security, brokers []string, topic, groupID string, t time.Time, log *zap.Logger) error {
+	dialer := sec.getDialer()
+	partitions, err := lookupPartitions(ctx, dialer, brokers, topic)
+	if err != nil {
+		return fmt.Errorf("failed to lookup partitions: %w", err)
+	}
+	client := &kafka.Client{Addr: kafka.TCP(brokers...), Transport: sec.getTransport()}
+	fetched, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{GroupID: groupID, Topics: map[string][]int{topic: partitions}})
+	if err != nil {
+		return fmt.Errorf("failed to fetch group offsets: %w", err)
+	}
+	if fetched.Error != nil {
+		return fmt.Errorf("failed to fetch group offsets: %w", fetched.Error)
+	}
+
+	var commits []kafka.OffsetCommit
+	for _, p := range fetched.Topics[topic] {
+		if p.Error != nil {
+			return fmt.Errorf("failed to fetch offset of partition %d: %w", p.Partition, p.Error)
+		}
+		if p.CommittedOffset >= 0 {
+			continue
+		}
+		offset, err := offsetAt(ctx, dialer, brokers, topic, p.Partition, t)
+		if err != nil {
+			return fmt.Errorf("failed to lookup offset of partition %d: %w", p.Partition, err)
+		}
+		commits = append(commits, kafka.OffsetCommit{Partition: p.Partition, Offset: offset})
+	}
+	if len(commits) == 0 {
+		return nil
+	}
+
+	// Коммит вне поколения группы возможен, только пока в группе нет участников.
+	// Если группа уже работает, offset'ы выставит reader по StartOffset.
+	res, err := client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      groupID,
+		GenerationID: -1,
+		Topics:       map[string][]kafka.OffsetCommit{topic: commits},
+	})
+	if err == nil {
+		for _, p := range res.Topics[topic] {
+			if p.Error != nil {
+				err = p.Error
+				break
+			}
+		}
+	}
+	if err != nil {
+		log.Warn("failed to seed group offsets by time", zap.String("group_id", groupID), zap.Error(err))
+		return nil
+	}
+	log.Info("group offsets seeded by time", zap.String("group_id", groupID), zap.Time("start_time", t), zap.Any("offsets", commits))
+	return nil
+}
+
+func lookupPartitions(ctx context.Context, dialer *kafka.Dialer, brokers []string, topic string) ([]int, error) {
+	var lastErr error
+	for _, broker := range brokers {
+		parts, err := dialer.LookupPartitions(ctx, "tcp", broker, topic)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		ids := make([]int, 0, len(parts))
+		for _, p := range parts {
+			ids = append(ids, p.ID)
+		}
+		return ids, nil
+	}
+	return nil, lastErr
+}
+
+// offsetAt возвращает offset первого сообщения партиции, записанного не раньше t,
+// или конец партиции, если таких сообщений нет.
+func offsetAt(ctx context.Context, dialer *kafka.Dialer, brokers []string, topic string, partition int, t time.Time) (int64, error) {
+	var lastErr error
+	for _, broker := range brokers {
+		conn, err := dialer.DialLeader(ctx, "tcp", broker, topic, partition)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		defer conn.Close()
+		offset, err := conn.ReadOffset(t)
+		if err != nil {
+			return 0, err
+		}
+		if offset < 0 {
+			return conn.ReadLastOffset()
+		}
+		return offset, nil
+	}
+	return 0, lastErr
+}
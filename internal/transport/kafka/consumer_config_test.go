@@ -0,0 +1,49 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerOptions_ReaderConfig(t *testing.T) {
+	opts := ConsumerOptions{
+		StartOffset:    "earliest",
+		MaxBytes:       1 << 20,
+		CommitInterval: time.Second,
+		Balancers:      []string{"round-robin", "range"},
+	}
+	cfg, startTime, err := opts.readerConfig([]string{"localhost:9092"}, "orders", "group")
+	require.NoError(t, err)
+	assert.Nil(t, startTime)
+	assert.Equal(t, kafka.FirstOffset, cfg.StartOffset)
+	assert.Equal(t, 1<<20, cfg.MaxBytes)
+	assert.Equal(t, time.Second, cfg.CommitInterval)
+	assert.Equal(t, []kafka.GroupBalancer{kafka.RoundRobinGroupBalancer{}, kafka.RangeGroupBalancer{}}, cfg.GroupBalancers)
+}
+
+func TestConsumerOptions_StartOffset(t *testing.T) {
+	offset, startTime, err := ConsumerOptions{}.startOffset()
+	require.NoError(t, err)
+	assert.Equal(t, kafka.LastOffset, offset)
+	assert.Nil(t, startTime)
+
+	// Для стартовой позиции по времени reader начинает с начала партиций,
+	// по которым не удалось выставить offset
+	offset, startTime, err = ConsumerOptions{StartOffset: "2025-01-01T00:00:00Z"}.startOffset()
+	require.NoError(t, err)
+	assert.Equal(t, kafka.FirstOffset, offset)
+	require.NotNil(t, startTime)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *startTime)
+
+	_, _, err = ConsumerOptions{StartOffset: "yesterday"}.startOffset()
+	assert.Error(t, err)
+}
+
+func TestConsumerOptions_UnknownBalancer(t *testing.T) {
+	_, _, err := ConsumerOptions{Balancers: []string{"sticky"}}.readerConfig(nil, "orders", "group")
+	assert.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package kafka
+
+import (
+	"errors"
+)
+
+// ErrConsumerDrained возвращается Resume после drain: consumer уже вышел из группы.
+var ErrConsumerDrained = errors.New("consumer is drained")
+
+// state возвращает признак паузы и канал, который закроется при следующем изменении состояния.
+func (c *Consumer) state() (bool, <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused, c.wake
+}
+
+// setPaused меняет признак паузы и будит Run. Вызывается под c.mu.
+func (c *Consumer) setPaused(paused bool) {
+	if c.paused == paused {
+		return
+	}
+	c.paused = paused
+	close(c.wake)
+	c.wake = make(chan struct{})
+}
+
+// Pause приостанавливает чтение сообщений. Обрабатываемое сообщение дообрабатывается и коммитится,
+// новые не читаются. Consumer остаётся в группе, поэтому его партиции не перераспределяются.
+func (c *Consumer) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setPaused(true)
+	c.log.Info("consumer paused")
+}
+
+// Resume возобновляет чтение сообщений после Pause.
+func (c *Consumer) Resume() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.drained {
+		return ErrConsumerDrained
+	}
+	c.setPaused(false)
+	c.log.Info("consumer resumed")
+	return nil
+}
+
+// Drain останавливает чтение, дожидается обработки и коммита текущего сообщения и закрывает
+// reader: отложенные коммиты отправляются, а consumer выходит из группы, чтобы его партиции
+// достались другим экземплярам. Возобновить чтение после drain нельзя — только перезапуском.
+func (c *Consumer) Drain() error {
+	c.mu.Lock()
+	if c.drained {
+		c.mu.Unlock()
+		return nil
+	}
+	c.setPaused(true)
+	c.drained = true
+	c.mu.Unlock()
+	c.log.Info("draining consumer")
+
+	// Run прерывает ожидание сообщения по паузе, поэтому блокировка освобождается
+	// сразу после обработки текущего сообщения
+	c.inFlight.Lock()
+	defer c.inFlight.Unlock()
+	if err := c.reader.Close(); err != nil {
+		return err
+	}
+	c.log.Info("consumer drained")
+	return nil
+}
+
+// Draining сообщает, переведён ли consumer в режим drain.
+func (c *Consumer) Draining() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.drained
+}
@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/transport/bus"
+)
+
+func TestConsumer_PauseResumeDrain(t *testing.T) {
+	noop := bus.HandlerFunc(func(context.Context, domain.Envelope) error { return nil })
+	// Брокер недоступен, поэтому Run всё время ждёт сообщения
+	c, err := NewConsumer(context.Background(), []string{"127.0.0.1:1"}, "orders", "group", ConsumerOptions{}, nil, noop, zap.NewNop())
+	require.NoError(t, err)
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+
+	c.Pause()
+	require.NoError(t, c.Resume())
+	c.Pause()
+
+	// Drain не ждёт сообщения, которое так и не придёт: ожидание прерывается паузой
+	drained := make(chan error, 1)
+	go func() { drained <- c.Drain() }()
+	select {
+	case err := <-drained:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("drain did not finish")
+	}
+	assert.True(t, c.Draining())
+	assert.ErrorIs(t, c.Resume(), ErrConsumerDrained)
+
+	// После drain Run остаётся на паузе до остановки приложения
+	select {
+	case err := <-done:
+		t.Fatalf("run stopped after drain: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
@@ -0,0 +1,199 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// PartitionStatus — состояние партиции топика заказов.
+type PartitionStatus struct {
+	Partition int `json:"partition"`
+	// Assigned — партиция назначена этому экземпляру приложения
+	Assigned bool `json:"assigned"`
+	// Offset — offset последнего сообщения, обработанного этим экземпляром (-1 — не было)
+	Offset int64 `json:"offset"`
+	// CommittedOffset — закоммиченный группой offset, т.е. следующее сообщение для чтения (-1 — коммитов не было)
+	CommittedOffset int64 `json:"committed_offset"`
+	// HighWatermark — offset, который получит следующее записанное в партицию сообщение
+	HighWatermark int64 `json:"high_watermark"`
+	// Lag — количество сообщений после закоммиченного (или последнего обработанного) offset'а
+	Lag             *int64     `json:"lag,omitempty"`
+	LastMessageTime *time.Time `json:"last_message_time,omitempty"`
+	Processed       int64      `json:"processed"`
+	// Errors — сообщения, обработка которых завершилась ошибкой
+	Errors int64 `json:"errors"`
+}
+
+// ReaderCounters — накопленные счётчики kafka.Reader.
+type ReaderCounters struct {
+	Dials         int64 `json:"dials"`
+	Fetches       int64 `json:"fetches"`
+	Messages      int64 `json:"messages"`
+	Bytes         int64 `json:"bytes"`
+	Rebalances    int64 `json:"rebalances"`
+	Timeouts      int64 `json:"timeouts"`
+	Errors        int64 `json:"errors"`
+	QueueLength   int64 `json:"queue_length"`
+	QueueCapacity int64 `json:"queue_capacity"`
+}
+
+// ConsumerStatus — состояние consumer'а.
+type ConsumerStatus struct {
+	Topic    string `json:"topic"`
+	GroupID  string `json:"group_id"`
+	ClientID string `json:"client_id"`
+	// MemberID — ID участника группы этого экземпляра, пусто, если он сейчас не в группе
+	MemberID     string `json:"member_id,omitempty"`
+	Paused       bool   `json:"paused"`
+	Draining     bool   `json:"draining"`
+	Processed    int64  `json:"processed"`
+	HandleErrors int64  `json:"handle_errors"`
+	CommitErrors int64  `json:"commit_errors"`
+	// TotalLag — суммарный лаг назначенных партиций
+	TotalLag   int64             `json:"total_lag"`
+	Reader     ReaderCounters    `json:"reader"`
+	Partitions []PartitionStatus `json:"partitions"`
+	// BrokerError — ошибка запроса состояния группы у брокеров. Назначения, закоммиченные
+	// offset'ы и high watermark'и в этом случае могут быть неполными
+	BrokerError string `json:"broker_error,omitempty"`
+}
+
+// consumerStats — внутренние счётчики consumer'а.
+type consumerStats struct {
+	partitions   map[int]*PartitionStatus
+	processed    int64
+	handleErrors int64
+	commitErrors int64
+	reader       ReaderCounters
+}
+
+// Status возвращает состояние consumer'а: внутренние счётчики дополняются назначением партиций,
+// закоммиченными offset'ами и high watermark'ами, полученными от брокеров.
+func (c *Consumer) Status(ctx context.Context) ConsumerStatus {
+	c.mu.Lock()
+	// Reader.Stats возвращает счётчики с момента предыдущего вызова, поэтому накапливаем их
+	rs := c.reader.Stats()
+	r := &c.stats.reader
+	r.Dials += rs.Dials
+	r.Fetches += rs.Fetches
+	r.Messages += rs.Messages
+	r.Bytes += rs.Bytes
+	r.Rebalances += rs.Rebalances
+	r.Timeouts += rs.Timeouts
+	r.Errors += rs.Errors
+	r.QueueLength, r.QueueCapacity = rs.QueueLength, rs.QueueCapacity
+
+	status := ConsumerStatus{
+		Topic:        c.topic,
+		GroupID:      c.groupID,
+		ClientID:     c.clientID,
+		Paused:       c.paused,
+		Draining:     c.drained,
+		Processed:    c.stats.processed,
+		HandleErrors: c.stats.handleErrors,
+		CommitErrors: c.stats.commitErrors,
+		Reader:       c.stats.reader,
+	}
+	partitions := make(map[int]*PartitionStatus, len(c.stats.partitions))
+	for id, p := range c.stats.partitions {
+		cp := *p
+		partitions[id] = &cp
+	}
+	c.mu.Unlock()
+
+	if err := c.brokerStatus(ctx, &status, partitions); err != nil {
+		status.BrokerError = err.Error()
+	}
+
+	status.Partitions = make([]PartitionStatus, 0, len(partitions))
+	for _, id := range slices.Sorted(maps.Keys(partitions)) {
+		p := partitions[id]
+		var lag int64
+		switch {
+		case p.CommittedOffset >= 0:
+			lag = p.HighWatermark - p.CommittedOffset
+		case p.Offset >= 0:
+			lag = p.HighWatermark - p.Offset - 1
+		default:
+			status.Partitions = append(status.Partitions, *p)
+			continue
+		}
+		lag = max(lag, 0)
+		p.Lag = &lag
+		if p.Assigned {
+			status.TotalLag += lag
+		}
+		status.Partitions = append(status.Partitions, *p)
+	}
+	return status
+}
+
+// brokerStatus запрашивает у брокеров назначение партиций, закоммиченные offset'ы и high watermark'и.
+func (c *Consumer) brokerStatus(ctx context.Context, status *ConsumerStatus, partitions map[int]*PartitionStatus) error {
+	ids, err := lookupPartitions(ctx, c.sec.getDialer(), c.brokers, c.topic)
+	if err != nil {
+		return fmt.Errorf("failed to lookup partitions: %w", err)
+	}
+	for _, id := range ids {
+		if _, ok := partitions[id]; !ok {
+			partitions[id] = &PartitionStatus{Partition: id, Offset: -1, CommittedOffset: -1}
+		}
+	}
+	client := &kafka.Client{Addr: kafka.TCP(c.brokers...), Transport: c.sec.getTransport()}
+
+	groups, err := client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{c.groupID}})
+	if err != nil {
+		return fmt.Errorf("failed to describe group: %w", err)
+	}
+	for _, g := range groups.Groups {
+		if g.Error != nil {
+			return fmt.Errorf("failed to describe group: %w", g.Error)
+		}
+		for _, m := range g.Members {
+			if m.ClientID != c.clientID {
+				continue
+			}
+			status.MemberID = m.MemberID
+			for _, t := range m.MemberAssignments.Topics {
+				if t.Topic != c.topic {
+					continue
+				}
+				for _, id := range t.Partitions {
+					if p, ok := partitions[id]; ok {
+						p.Assigned = true
+					}
+				}
+			}
+		}
+	}
+
+	offsets, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{GroupID: c.groupID, Topics: map[string][]int{c.topic: ids}})
+	if err != nil {
+		return fmt.Errorf("failed to fetch committed offsets: %w", err)
+	}
+	for _, o := range offsets.Topics[c.topic] {
+		if p, ok := partitions[o.Partition]; ok && o.Error == nil {
+			p.CommittedOffset = o.CommittedOffset
+		}
+	}
+
+	requests := make([]kafka.OffsetRequest, 0, len(ids))
+	for _, id := range ids {
+		requests = append(requests, kafka.LastOffsetOf(id))
+	}
+	watermarks, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{Topics: map[string][]kafka.OffsetRequest{c.topic: requests}})
+	if err != nil {
+		return fmt.Errorf("failed to list offsets: %w", err)
+	}
+	for _, o := range watermarks.Topics[c.topic] {
+		if p, ok := partitions[o.Partition]; ok && o.Error == nil {
+			p.HighWatermark = o.LastOffset
+		}
+	}
+	return nil
+}
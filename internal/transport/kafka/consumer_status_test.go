@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/transport/bus"
+)
+
+func TestConsumer_StatusWithoutBrokers(t *testing.T) {
+	// Брокер недоступен: состояние строится только по внутренним счётчикам
+	noop := bus.HandlerFunc(func(context.Context, domain.Envelope) error { return nil })
+	c, err := NewConsumer(context.Background(), []string{"127.0.0.1:1"}, "orders", "group", ConsumerOptions{}, nil, noop, zap.NewNop())
+	require.NoError(t, err)
+	defer c.Close()
+
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.record(kafka.Message{Partition: 1, Offset: 10, HighWaterMark: 15, Time: at}, nil, nil)
+	c.record(kafka.Message{Partition: 1, Offset: 11, HighWaterMark: 15, Time: at}, errors.New("invalid order"), nil)
+	c.record(kafka.Message{Partition: 0, Offset: 3, HighWaterMark: 4, Time: at}, nil, errors.New("commit failed"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status := c.Status(ctx)
+
+	assert.NotEmpty(t, status.BrokerError)
+	assert.Equal(t, "group", status.GroupID)
+	assert.Equal(t, int64(3), status.Processed)
+	assert.Equal(t, int64(1), status.HandleErrors)
+	assert.Equal(t, int64(1), status.CommitErrors)
+	require.Len(t, status.Partitions, 2)
+
+	p := status.Partitions[1]
+	assert.Equal(t, 1, p.Partition)
+	assert.Equal(t, int64(11), p.Offset)
+	assert.Equal(t, int64(-1), p.CommittedOffset)
+	assert.Equal(t, int64(2), p.Processed)
+	assert.Equal(t, int64(1), p.Errors)
+	require.NotNil(t, p.Lag)
+	assert.Equal(t, int64(3), *p.Lag)
+	assert.Equal(t, at, *p.LastMessageTime)
+
+	require.NotNil(t, status.Partitions[0].Lag)
+	assert.Equal(t, int64(0), *status.Partitions[0].Lag)
+}
@@ -0,0 +1,93 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/transport/bus"
+)
+
+// fakePublisher запоминает опубликованные сообщения или возвращает err.
+type fakePublisher struct {
+	msgs []bus.Message
+	err  error
+}
+
+func (p *fakePublisher) Publish(_ context.Context, msg bus.Message) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func (p *fakePublisher) PublishBatch(ctx context.Context, msgs []bus.Message) []error {
+	errs := make([]error, len(msgs))
+	for i, msg := range msgs {
+		errs[i] = p.Publish(ctx, msg)
+	}
+	return errs
+}
+
+func (p *fakePublisher) Close() error { return nil }
+
+func TestConsumerHandle_RoutesFailuresToDLQ(t *testing.T) {
+	errInvalid := errors.New("order validation failed")
+	handler := bus.HandlerFunc(func(_ context.Context, env domain.Envelope) error {
+		if env.Key == "bad" {
+			return errInvalid
+		}
+		return nil
+	})
+	dlq := &fakePublisher{}
+	c := &Consumer{svc: handler, dlq: dlq, log: zap.NewNop()}
+	ctx := context.Background()
+
+	handleErr, err := c.handle(ctx, kafka.Message{Topic: "orders", Key: []byte("good"), Value: []byte("{}")})
+	require.NoError(t, err)
+	assert.NoError(t, handleErr)
+	assert.Empty(t, dlq.msgs)
+
+	bad := kafka.Message{
+		Topic: "orders", Partition: 2, Offset: 42, Key: []byte("bad"), Value: []byte("{"),
+		Headers: []kafka.Header{{Key: domain.HeaderCorrelationID, Value: []byte("corr-1")}},
+	}
+	handleErr, err = c.handle(ctx, bad)
+	require.NoError(t, err)
+	assert.ErrorIs(t, handleErr, errInvalid)
+	require.Len(t, dlq.msgs, 1)
+	assert.Equal(t, bus.Message{
+		Key:   "bad",
+		Value: []byte("{"),
+		Headers: map[string]string{
+			domain.HeaderCorrelationID: "corr-1",
+			HeaderDLQError:             "order validation failed",
+			HeaderDLQTopic:             "orders",
+			HeaderDLQPartition:         "2",
+			HeaderDLQOffset:            "42",
+		},
+	}, dlq.msgs[0])
+
+	// Сообщение, которое не удалось отправить в DLQ, не должно быть закоммичено
+	dlq.err = errors.New("broker unavailable")
+	_, err = c.handle(ctx, bad)
+	assert.Error(t, err)
+}
+
+func TestConsumerHandle_WithoutDLQ(t *testing.T) {
+	errInvalid := errors.New("order validation failed")
+	c := &Consumer{
+		svc: bus.HandlerFunc(func(context.Context, domain.Envelope) error { return errInvalid }),
+		log: zap.NewNop(),
+	}
+	handleErr, err := c.handle(context.Background(), kafka.Message{Key: []byte("bad")})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, handleErr, errInvalid)
+}
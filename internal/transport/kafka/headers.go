@@ -0,0 +1,56 @@
+package kafka
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/transport/bus"
+)
+
+// message переводит сообщение шины в сообщение Kafka. Заголовки сортируются по имени,
+// чтобы одинаковые сообщения записывались одинаково.
+func message(m bus.Message) kafka.Message {
+	msg := kafka.Message{Key: []byte(m.Key), Value: m.Value}
+	for _, name := range slices.Sorted(maps.Keys(m.Headers)) {
+		msg.Headers = append(msg.Headers, kafka.Header{Key: name, Value: []byte(m.Headers[name])})
+	}
+	return msg
+}
+
+// Заголовки, которые добавляются к сообщению при отправке в DLQ
+const (
+	HeaderDLQError     = "dlq-error"
+	HeaderDLQTopic     = "dlq-topic"
+	HeaderDLQPartition = "dlq-partition"
+	HeaderDLQOffset    = "dlq-offset"
+)
+
+// dlqMessage возвращает сообщение для DLQ: ключ, тело и заголовки исходного сообщения,
+// а также ошибку обработки и позицию сообщения в исходном топике.
+func dlqMessage(m kafka.Message, handleErr error) bus.Message {
+	env := envelope(m)
+	headers := make(map[string]string, len(env.Headers)+4)
+	maps.Copy(headers, env.Headers)
+	headers[HeaderDLQError] = handleErr.Error()
+	headers[HeaderDLQTopic] = m.Topic
+	headers[HeaderDLQPartition] = strconv.Itoa(m.Partition)
+	headers[HeaderDLQOffset] = strconv.FormatInt(m.Offset, 10)
+	return bus.Message{Key: env.Key, Value: env.Payload, Headers: headers}
+}
+
+// envelope переводит прочитанное сообщение Kafka в domain.Envelope. Из повторяющихся
+// заголовков используется последний.
+func envelope(m kafka.Message) domain.Envelope {
+	env := domain.Envelope{Key: string(m.Key), Payload: m.Value}
+	if len(m.Headers) > 0 {
+		env.Headers = make(map[string]string, len(m.Headers))
+		for _, h := range m.Headers {
+			env.Headers[h.Key] = string(h.Value)
+		}
+	}
+	return env
+}
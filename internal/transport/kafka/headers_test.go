@@ -0,0 +1,31 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/transport/bus"
+)
+
+func TestMessageEnvelopeRoundTrip(t *testing.T) {
+	headers := map[string]string{
+		domain.HeaderSourceSystem:  "crm",
+		domain.HeaderCorrelationID: "corr-1",
+	}
+	msg := message(bus.Message{Key: "order-1", Value: []byte("{}"), Headers: headers})
+	assert.Equal(t, []kafka.Header{
+		{Key: domain.HeaderCorrelationID, Value: []byte("corr-1")},
+		{Key: domain.HeaderSourceSystem, Value: []byte("crm")},
+	}, msg.Headers)
+
+	env := envelope(msg)
+	assert.Equal(t, "order-1", env.Key)
+	assert.Equal(t, []byte("{}"), env.Payload)
+	assert.Equal(t, headers, env.Headers)
+
+	// Сообщение без заголовков
+	assert.Nil(t, envelope(kafka.Message{Key: []byte("order-2")}).Headers)
+}
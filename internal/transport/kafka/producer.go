@@ -2,35 +2,107 @@ package kafka
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/segmentio/kafka-go"
 	"go.uber.org/zap"
+
+	"wb-l0-go/internal/transport/bus"
 )
 
+// Producer публикует сообщения в топик Kafka. Реализует bus.Publisher.
 type Producer struct {
-	writer *kafka.Writer
-	log    *zap.Logger
+	writer    *kafka.Writer
+	log       *zap.Logger
+	batchSize int
+}
+
+// ProducerOptions задаёт настройки записи в топик.
+type ProducerOptions struct {
+	// BatchSize — максимальное количество сообщений в одном запросе к брокеру
+	BatchSize int
+	// Compression — сжатие пачек сообщений: none, gzip, snappy, lz4 или zstd. Consumer
+	// распаковывает сообщения сам, поэтому настройка задаётся только на стороне producer'а.
+	Compression string
+}
+
+// compression возвращает кодек сжатия kafka-go. Нулевое значение означает запись без сжатия.
+func (o ProducerOptions) compression() (kafka.Compression, error) {
+	switch strings.ToLower(o.Compression) {
+	case "", "none":
+		return 0, nil
+	case "gzip":
+		return kafka.Gzip, nil
+	case "snappy":
+		return kafka.Snappy, nil
+	case "lz4":
+		return kafka.Lz4, nil
+	case "zstd":
+		return kafka.Zstd, nil
+	}
+	return 0, fmt.Errorf("unsupported compression %q, expected none, gzip, snappy, lz4 or zstd", o.Compression)
 }
 
-func NewProducer(brokers []string, topic string, log *zap.Logger) *Producer {
+func NewProducer(brokers []string, topic string, opts ProducerOptions, sec *Security, log *zap.Logger) (*Producer, error) {
+	compression, err := opts.compression()
+	if err != nil {
+		return nil, err
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
 	w := &kafka.Writer{
 		Addr:                   kafka.TCP(brokers...),
+		Transport:              sec.getTransport(),
 		Topic:                  topic,
 		Balancer:               &kafka.Hash{},
 		AllowAutoTopicCreation: true,
 		RequiredAcks:           kafka.RequireAll,
 		BatchTimeout:           10 * time.Millisecond,
+		BatchSize:              batchSize,
+		Compression:            compression,
 	}
-	return &Producer{writer: w, log: log}
+	return &Producer{writer: w, log: log, batchSize: batchSize}, nil
+}
+
+func (p *Producer) Publish(ctx context.Context, msg bus.Message) error {
+	return p.writer.WriteMessages(ctx, message(msg))
 }
 
-func (p *Producer) Publish(ctx context.Context, key string, value []byte) error {
-	msg := kafka.Message{
-		Key:   []byte(key),
-		Value: value,
+// PublishBatch публикует сообщения пачками по batchSize штук за один вызов WriteMessages.
+// Возвращает срез ошибок той же длины, что и msgs: nil означает, что сообщение опубликовано.
+func (p *Producer) PublishBatch(ctx context.Context, msgs []bus.Message) []error {
+	errs := make([]error, len(msgs))
+	for start := 0; start < len(msgs); start += p.batchSize {
+		end := min(start+p.batchSize, len(msgs))
+
+		batch := make([]kafka.Message, 0, end-start)
+		for _, m := range msgs[start:end] {
+			batch = append(batch, message(m))
+		}
+
+		err := p.writer.WriteMessages(ctx, batch...)
+		if err == nil {
+			continue
+		}
+		// kafka-go возвращает WriteErrors, если часть сообщений пачки не записалась
+		var writeErrs kafka.WriteErrors
+		if errors.As(err, &writeErrs) && len(writeErrs) == len(batch) {
+			for i, werr := range writeErrs {
+				errs[start+i] = werr
+			}
+			continue
+		}
+		for i := start; i < end; i++ {
+			errs[i] = err
+		}
+		p.log.Error("failed to publish batch", zap.Int("size", len(batch)), zap.Error(err))
 	}
-	return p.writer.WriteMessages(ctx, msg)
+	return errs
 }
 
 func (p *Producer) Close() error {
@@ -0,0 +1,22 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewProducer_Compression(t *testing.T) {
+	for name, want := range map[string]kafka.Compression{"": 0, "none": 0, "gzip": kafka.Gzip, "Snappy": kafka.Snappy, "lz4": kafka.Lz4, "zstd": kafka.Zstd} {
+		p, err := NewProducer([]string{"localhost:9092"}, "orders", ProducerOptions{Compression: name}, nil, zap.NewNop())
+		require.NoError(t, err, name)
+		assert.Equal(t, want, p.writer.Compression, name)
+		assert.Equal(t, 100, p.batchSize)
+	}
+
+	_, err := NewProducer([]string{"localhost:9092"}, "orders", ProducerOptions{Compression: "brotli"}, nil, zap.NewNop())
+	assert.ErrorContains(t, err, "unsupported compression")
+}
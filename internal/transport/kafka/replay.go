@@ -0,0 +1,291 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/service"
+	"wb-l0-go/internal/transport/bus"
+)
+
+// Максимальное количество ошибок в отчёте о повторной обработке
+const maxReplayErrors = 100
+
+// ReplayOptions задаёт диапазон сообщений для повторной обработки.
+// Начало задаётся либо FromOffset, либо FromTime. Если конец не задан,
+// обрабатываются сообщения, записанные в топик к моменту старта.
+type ReplayOptions struct {
+	// DLQ — читать DLQ топик вместо основного топика заказов
+	DLQ bool `json:"dlq"`
+	// Partition — номер партиции, nil — все партиции топика
+	Partition *int `json:"partition,omitempty"`
+	// FromOffset — первый обрабатываемый offset в каждой партиции
+	FromOffset *int64 `json:"from_offset,omitempty"`
+	// FromTime — обрабатывать сообщения, записанные не раньше этого времени
+	FromTime *time.Time `json:"from_time,omitempty"`
+	// ToOffset — последний обрабатываемый offset (включительно)
+	ToOffset *int64 `json:"to_offset,omitempty"`
+	// ToTime — обрабатывать сообщения, записанные раньше этого времени
+	ToTime *time.Time `json:"to_time,omitempty"`
+	// Apply — сохранять заказы через HandleOrder. Иначе выполняется только разбор и валидация (dry-run)
+	Apply bool `json:"apply"`
+}
+
+func (o ReplayOptions) validate() error {
+	if (o.FromOffset == nil) == (o.FromTime == nil) {
+		return errors.New("exactly one of from_offset and from_time is required")
+	}
+	if o.FromOffset != nil && *o.FromOffset < 0 {
+		return errors.New("from_offset must not be negative")
+	}
+	if o.Partition != nil && *o.Partition < 0 {
+		return errors.New("partition must not be negative")
+	}
+	return nil
+}
+
+type ReplayPartitionReport struct {
+	Partition   int   `json:"partition"`
+	StartOffset int64 `json:"start_offset"`
+	// EndOffset — offset, на котором обработка остановилась (не включительно)
+	EndOffset int64 `json:"end_offset"`
+	Processed int   `json:"processed"`
+}
+
+type ReplayError struct {
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+	Key       string `json:"key,omitempty"`
+	Error     string `json:"error"`
+}
+
+// ReplayReport — итог повторной обработки.
+type ReplayReport struct {
+	Topic      string                  `json:"topic"`
+	Mode       string                  `json:"mode"`
+	Partitions []ReplayPartitionReport `json:"partitions"`
+	Processed  int                     `json:"processed"`
+	// Accepted — заказы, прошедшие валидацию (и сохранённые в режиме apply)
+	Accepted int `json:"accepted"`
+	// Rejected — сообщения, которые не удалось разобрать или не прошедшие валидацию
+	Rejected int `json:"rejected"`
+	// Failed — сообщения, которые не удалось сохранить
+	Failed          int           `json:"failed"`
+	Errors          []ReplayError `json:"errors"`
+	ErrorsTruncated bool          `json:"errors_truncated"`
+}
+
+func (r *ReplayReport) addError(e ReplayError) {
+	if len(r.Errors) >= maxReplayErrors {
+		r.ErrorsTruncated = true
+		return
+	}
+	r.Errors = append(r.Errors, e)
+}
+
+// Replayer повторно обрабатывает уже прочитанные consumer'ом сообщения. Он читает партиции
+// напрямую, без группы, поэтому не влияет на закоммиченные offset'ы основного consumer'а.
+type Replayer struct {
+	brokers  []string
+	topic    string
+	dlqTopic string
+	svc      *service.OrderService
+	log      *zap.Logger
+	dialer   *kafka.Dialer
+}
+
+func NewReplayer(brokers []string, topic, dlqTopic string, sec *Security, svc *service.OrderService, log *zap.Logger) *Replayer {
+	return &Replayer{
+		brokers:  brokers,
+		topic:    topic,
+		dlqTopic: dlqTopic,
+		svc:      svc,
+		log:      log,
+		dialer:   sec.getDialer(),
+	}
+}
+
+// ErrInvalidReplayOptions возвращается Replay при некорректных параметрах.
+var ErrInvalidReplayOptions = errors.New("invalid replay options")
+
+func (r *Replayer) Replay(ctx context.Context, opts ReplayOptions) (ReplayReport, error) {
+	if err := opts.validate(); err != nil {
+		return ReplayReport{}, fmt.Errorf("%w: %w", ErrInvalidReplayOptions, err)
+	}
+
+	report := ReplayReport{Topic: r.topic, Mode: "dry-run", Partitions: []ReplayPartitionReport{}, Errors: []ReplayError{}}
+	if opts.DLQ {
+		report.Topic = r.dlqTopic
+	}
+	if opts.Apply {
+		report.Mode = "apply"
+	}
+
+	partitions, err := r.partitions(ctx, report.Topic)
+	if err != nil {
+		return report, fmt.Errorf("failed to lookup partitions: %w", err)
+	}
+	if opts.Partition != nil {
+		found := false
+		for _, p := range partitions {
+			if p == *opts.Partition {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return report, fmt.Errorf("%w: partition %d not found in topic %s", ErrInvalidReplayOptions, *opts.Partition, report.Topic)
+		}
+		partitions = []int{*opts.Partition}
+	}
+
+	log := r.log.With(zap.String("topic", report.Topic), zap.String("mode", report.Mode))
+	log.Info("replay started", zap.Ints("partitions", partitions))
+	for _, p := range partitions {
+		if err := r.replayPartition(ctx, report.Topic, p, opts, &report); err != nil {
+			return report, fmt.Errorf("failed to replay partition %d: %w", p, err)
+		}
+	}
+	log.Info("replay finished",
+		zap.Int("processed", report.Processed),
+		zap.Int("accepted", report.Accepted),
+		zap.Int("rejected", report.Rejected),
+		zap.Int("failed", report.Failed))
+	return report, nil
+}
+
+// partitions возвращает номера партиций топика, опрашивая брокеры по очереди.
+func (r *Replayer) partitions(ctx context.Context, topic string) ([]int, error) {
+	return lookupPartitions(ctx, r.dialer, r.brokers, topic)
+}
+
+// bounds вычисляет диапазон offset'ов [start, end) партиции для заданных опций.
+func (r *Replayer) bounds(ctx context.Context, topic string, partition int, opts ReplayOptions) (int64, int64, error) {
+	var lastErr error
+	for _, broker := range r.brokers {
+		conn, err := r.dialer.DialLeader(ctx, "tcp", broker, topic, partition)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		defer conn.Close()
+		return replayBounds(conn, opts)
+	}
+	return 0, 0, lastErr
+}
+
+// offsetReader — методы kafka.Conn, по которым вычисляется диапазон повторной обработки.
+type offsetReader interface {
+	ReadOffsets() (first, last int64, err error)
+	ReadOffset(t time.Time) (int64, error)
+}
+
+// replayBounds вычисляет диапазон [start, end) по границам партиции. Kafka возвращает -1 на запрос
+// offset'а по времени, если после этого времени сообщений нет, — такой offset заменяется концом партиции.
+// Если start >= end, обрабатывать нечего.
+func replayBounds(conn offsetReader, opts ReplayOptions) (int64, int64, error) {
+	first, last, err := conn.ReadOffsets()
+	if err != nil {
+		return 0, 0, err
+	}
+	offsetAt := func(t time.Time) (int64, error) {
+		offset, err := conn.ReadOffset(t)
+		if err != nil {
+			return 0, err
+		}
+		if offset < 0 {
+			return last, nil
+		}
+		return offset, nil
+	}
+
+	start, end := first, last
+	switch {
+	case opts.FromOffset != nil && opts.FromTime != nil:
+		return 0, 0, fmt.Errorf("%w: from_offset and from_time are mutually exclusive", ErrInvalidReplayOptions)
+	case opts.FromOffset != nil:
+		start = min(max(*opts.FromOffset, first), last)
+	case opts.FromTime != nil:
+		if start, err = offsetAt(*opts.FromTime); err != nil {
+			return 0, 0, err
+		}
+	}
+	if opts.ToOffset != nil {
+		end = min(end, *opts.ToOffset+1)
+	}
+	if opts.ToTime != nil {
+		to, err := offsetAt(*opts.ToTime)
+		if err != nil {
+			return 0, 0, err
+		}
+		end = min(end, to)
+	}
+	return start, max(start, end), nil
+}
+
+func (r *Replayer) replayPartition(ctx context.Context, topic string, partition int, opts ReplayOptions, report *ReplayReport) error {
+	start, end, err := r.bounds(ctx, topic, partition, opts)
+	if err != nil {
+		return err
+	}
+	pr := ReplayPartitionReport{Partition: partition, StartOffset: start, EndOffset: start}
+	defer func() { report.Partitions = append(report.Partitions, pr) }()
+	if start >= end {
+		return nil
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   r.brokers,
+		Topic:     topic,
+		Partition: partition,
+		Dialer:    r.dialer,
+		MinBytes:  1,
+		MaxBytes:  10e6,
+		MaxWait:   500 * time.Millisecond,
+	})
+	defer reader.Close()
+	if err := reader.SetOffset(start); err != nil {
+		return err
+	}
+
+	for pr.EndOffset < end {
+		m, err := reader.FetchMessage(ctx)
+		if err != nil {
+			return err
+		}
+		if m.Offset >= end {
+			break
+		}
+		pr.EndOffset = m.Offset + 1
+		pr.Processed++
+		report.Processed++
+		r.process(ctx, m, opts.Apply, report)
+	}
+	return nil
+}
+
+func (r *Replayer) process(ctx context.Context, m kafka.Message, apply bool, report *ReplayReport) {
+	var err error
+	env := envelope(m)
+	if apply {
+		err = r.svc.HandleOrder(bus.MessageContext(ctx, env), env)
+	} else {
+		_, err = r.svc.CheckOrder(env)
+	}
+
+	switch {
+	case err == nil:
+		report.Accepted++
+		return
+	case errors.Is(err, service.ErrOrderMalformed), errors.Is(err, service.ErrOrderInvalid):
+		report.Rejected++
+	default:
+		report.Failed++
+	}
+	report.addError(ReplayError{Partition: m.Partition, Offset: m.Offset, Key: string(m.Key), Error: err.Error()})
+}
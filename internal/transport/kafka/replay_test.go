@@ -0,0 +1,67 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOffsets — партиция с offset'ами [first, last), сообщения записаны по одному в секунду начиная с base.
+type fakeOffsets struct {
+	first, last int64
+	base        time.Time
+}
+
+func (f fakeOffsets) ReadOffsets() (int64, int64, error) {
+	return f.first, f.last, nil
+}
+
+func (f fakeOffsets) ReadOffset(t time.Time) (int64, error) {
+	offset := f.first + int64(max(t.Sub(f.base), 0)/time.Second)
+	if offset >= f.last {
+		return -1, nil
+	}
+	return offset, nil
+}
+
+func TestReplayBounds(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	conn := fakeOffsets{first: 10, last: 20, base: base}
+	at := func(d time.Duration) *time.Time { t := base.Add(d); return &t }
+	offset := func(o int64) *int64 { return &o }
+
+	start, end, err := replayBounds(conn, ReplayOptions{FromTime: at(2 * time.Second), ToTime: at(5 * time.Second)})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{12, 15}, []int64{start, end})
+
+	// to_time после последнего сообщения — до конца партиции
+	start, end, err = replayBounds(conn, ReplayOptions{FromTime: at(2 * time.Second), ToTime: at(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{12, 20}, []int64{start, end})
+
+	// from_time после последнего сообщения — пустой диапазон в конце партиции
+	start, end, err = replayBounds(conn, ReplayOptions{FromTime: at(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{20, 20}, []int64{start, end})
+
+	// from_offset за концом партиции и to_offset перед началом диапазона тоже дают пустой диапазон
+	start, end, err = replayBounds(conn, ReplayOptions{FromOffset: offset(100)})
+	require.NoError(t, err)
+	assert.Equal(t, start, end)
+	start, end, err = replayBounds(conn, ReplayOptions{FromOffset: offset(15), ToOffset: offset(12)})
+	require.NoError(t, err)
+	assert.Equal(t, start, end)
+
+	_, _, err = replayBounds(conn, ReplayOptions{FromOffset: offset(12), FromTime: at(0)})
+	assert.ErrorIs(t, err, ErrInvalidReplayOptions)
+}
+
+func TestReplayOptions_Validate(t *testing.T) {
+	from := time.Now()
+	offset := int64(0)
+	assert.Error(t, ReplayOptions{}.validate())
+	assert.Error(t, ReplayOptions{FromOffset: &offset, FromTime: &from}.validate())
+	assert.NoError(t, ReplayOptions{FromTime: &from}.validate())
+}
@@ -0,0 +1,131 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// SecurityConfig — параметры защищённого подключения к брокерам.
+type SecurityConfig struct {
+	TLS bool
+	// CAFile — PEM файл с сертификатами удостоверяющих центров. Пусто — системные сертификаты
+	CAFile string
+	// CertFile и KeyFile — клиентский сертификат и ключ для mTLS
+	CertFile           string
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
+	// SASLMechanism — PLAIN, SCRAM-SHA-256 или SCRAM-SHA-512. Пусто — без SASL
+	SASLMechanism string
+	SASLUsername  string
+	SASLPassword  string
+}
+
+// Security хранит dialer для reader'ов и transport для writer'ов и клиентов, настроенные
+// одинаково. nil *Security означает подключение без TLS и SASL.
+type Security struct {
+	dialer    *kafka.Dialer
+	transport kafka.RoundTripper
+}
+
+func NewSecurity(cfg SecurityConfig) (*Security, error) {
+	tlsConfig, err := cfg.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	mechanism, err := cfg.saslMechanism()
+	if err != nil {
+		return nil, err
+	}
+	return &Security{
+		dialer: &kafka.Dialer{
+			Timeout:       10 * time.Second,
+			DualStack:     true,
+			TLS:           tlsConfig,
+			SASLMechanism: mechanism,
+		},
+		transport: &kafka.Transport{
+			TLS:  tlsConfig,
+			SASL: mechanism,
+		},
+	}, nil
+}
+
+func (s *Security) getDialer() *kafka.Dialer {
+	if s == nil {
+		return kafka.DefaultDialer
+	}
+	return s.dialer
+}
+
+func (s *Security) getTransport() kafka.RoundTripper {
+	if s == nil {
+		return kafka.DefaultTransport
+	}
+	return s.transport
+}
+
+func (c SecurityConfig) tlsConfig() (*tls.Config, error) {
+	if !c.TLS {
+		if c.CAFile != "" || c.CertFile != "" {
+			return nil, errors.New("TLS certificates are set but TLS is disabled")
+		}
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errors.New("both client certificate and key are required")
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func (c SecurityConfig) saslMechanism() (sasl.Mechanism, error) {
+	name := strings.ToUpper(strings.TrimSpace(c.SASLMechanism))
+	if name == "" {
+		return nil, nil
+	}
+	if c.SASLUsername == "" {
+		return nil, errors.New("SASL username is required")
+	}
+	switch name {
+	case "PLAIN":
+		return plain.Mechanism{Username: c.SASLUsername, Password: c.SASLPassword}, nil
+	case "SCRAM-SHA-256":
+		return scram.Mechanism(scram.SHA256, c.SASLUsername, c.SASLPassword)
+	case "SCRAM-SHA-512":
+		return scram.Mechanism(scram.SHA512, c.SASLUsername, c.SASLPassword)
+	}
+	return nil, fmt.Errorf("unsupported SASL mechanism %q", c.SASLMechanism)
+}
@@ -0,0 +1,86 @@
+package kafka
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate создаёт самоподписанный сертификат и ключ в каталоге dir.
+func writeCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kafka-test"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func TestNewSecurity_TLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t, t.TempDir())
+
+	sec, err := NewSecurity(SecurityConfig{
+		TLS:           true,
+		CAFile:        certFile,
+		CertFile:      certFile,
+		KeyFile:       keyFile,
+		SASLMechanism: "scram-sha-512",
+		SASLUsername:  "user",
+		SASLPassword:  "secret",
+	})
+	require.NoError(t, err)
+	dialer := sec.getDialer()
+	require.NotNil(t, dialer.TLS)
+	assert.NotNil(t, dialer.TLS.RootCAs)
+	assert.Len(t, dialer.TLS.Certificates, 1)
+	require.NotNil(t, dialer.SASLMechanism)
+	assert.Equal(t, "SCRAM-SHA-512", dialer.SASLMechanism.Name())
+}
+
+func TestNewSecurity_Plaintext(t *testing.T) {
+	sec, err := NewSecurity(SecurityConfig{SASLMechanism: "PLAIN", SASLUsername: "user"})
+	require.NoError(t, err)
+	assert.Nil(t, sec.getDialer().TLS)
+	assert.Equal(t, "PLAIN", sec.getDialer().SASLMechanism.Name())
+}
+
+func TestNewSecurity_Invalid(t *testing.T) {
+	cases := map[string]SecurityConfig{
+		"unknown mechanism": {SASLMechanism: "GSSAPI", SASLUsername: "user"},
+		"missing username":  {SASLMechanism: "PLAIN"},
+		"missing key":       {TLS: true, CertFile: "client.pem"},
+		"certs without tls": {CAFile: "ca.pem"},
+		"missing ca bundle": {TLS: true, CAFile: filepath.Join(t.TempDir(), "ca.pem")},
+	}
+	for name, cfg := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewSecurity(cfg)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,288 @@
+// Package nats реализует шину сообщений (transport/bus) поверх NATS JetStream.
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/logger"
+	"wb-l0-go/internal/transport/bus"
+)
+
+// Заголовок, в котором передаётся ключ сообщения (аналог ключа сообщения Kafka)
+const keyHeader = "Order-Key"
+
+// Максимальное время ожидания подтверждений асинхронных публикаций при закрытии Publisher
+const publishCompleteWait = 5 * time.Second
+
+// Client — подключение к NATS с контекстом JetStream. Подключение общее для всех Publisher
+// и Subscriber клиента и закрывается, когда закрыт последний из них.
+type Client struct {
+	conn *nats.Conn
+	js   jetstream.JetStream
+	log  *zap.Logger
+	// closed закрывается, когда подключение закрыто после drain
+	closed chan struct{}
+
+	mu    sync.Mutex
+	users int
+}
+
+func Connect(url string, log *zap.Logger) (*Client, error) {
+	closed := make(chan struct{})
+	conn, err := nats.Connect(url,
+		nats.Name("wb-l0-go"),
+		nats.ClosedHandler(func(*nats.Conn) { close(closed) }),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			log.Warn("nats disconnected", zap.Error(err))
+		}),
+		nats.ReconnectHandler(func(c *nats.Conn) {
+			log.Info("nats reconnected", zap.String("url", c.ConnectedUrl()))
+		}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to init jetstream: %w", err)
+	}
+	return &Client{conn: conn, js: js, log: log, closed: closed}, nil
+}
+
+// EnsureStream создаёт поток stream, хранящий сообщения subject, или обновляет его настройки.
+func (c *Client) EnsureStream(ctx context.Context, stream, subject string) error {
+	_, err := c.js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     stream,
+		Subjects: []string{subject},
+		Storage:  jetstream.FileStorage,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create stream %s: %w", stream, err)
+	}
+	return nil
+}
+
+// Close дожидается отправки опубликованных сообщений и закрывает подключение. Нужен, только
+// если клиент не успел создать Publisher и Subscriber: иначе подключение закрывает последний из них.
+func (c *Client) Close() error {
+	if err := c.conn.Drain(); err != nil {
+		if errors.Is(err, nats.ErrConnectionClosed) {
+			return nil
+		}
+		if !errors.Is(err, nats.ErrConnectionDraining) {
+			return fmt.Errorf("failed to drain nats connection: %w", err)
+		}
+	}
+	<-c.closed
+	return nil
+}
+
+func (c *Client) acquire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.users++
+}
+
+// release освобождает подключение, последний пользователь закрывает его.
+func (c *Client) release() error {
+	c.mu.Lock()
+	c.users--
+	last := c.users == 0
+	c.mu.Unlock()
+	if !last {
+		return nil
+	}
+	return c.Close()
+}
+
+// Publisher публикует сообщения в subject. Реализует bus.Publisher.
+type Publisher struct {
+	client    *Client
+	js        jetstream.JetStream
+	subject   string
+	closeOnce sync.Once
+}
+
+func (c *Client) Publisher(subject string) *Publisher {
+	c.acquire()
+	return &Publisher{client: c, js: c.js, subject: subject}
+}
+
+func (p *Publisher) message(m bus.Message) *nats.Msg {
+	msg := nats.NewMsg(p.subject)
+	msg.Data = m.Value
+	for name, value := range m.Headers {
+		msg.Header.Set(name, value)
+	}
+	if m.Key != "" {
+		msg.Header.Set(keyHeader, m.Key)
+	}
+	return msg
+}
+
+func (p *Publisher) Publish(ctx context.Context, msg bus.Message) error {
+	_, err := p.js.PublishMsg(ctx, p.message(msg))
+	return err
+}
+
+// PublishBatch публикует сообщения асинхронно и дожидается подтверждения каждого.
+func (p *Publisher) PublishBatch(ctx context.Context, msgs []bus.Message) []error {
+	errs := make([]error, len(msgs))
+	futures := make([]jetstream.PubAckFuture, len(msgs))
+	for i, m := range msgs {
+		futures[i], errs[i] = p.js.PublishMsgAsync(p.message(m))
+	}
+	for i, f := range futures {
+		if f == nil {
+			continue
+		}
+		select {
+		case <-f.Ok():
+		case err := <-f.Err():
+			errs[i] = err
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+		}
+	}
+	return errs
+}
+
+// Close дожидается подтверждений асинхронных публикаций и освобождает подключение.
+func (p *Publisher) Close() error {
+	var err error
+	p.closeOnce.Do(func() {
+		select {
+		case <-p.js.PublishAsyncComplete():
+		case <-time.After(publishCompleteWait):
+			p.client.log.Warn("nats publisher closed with pending acks", zap.String("subject", p.subject))
+		}
+		err = p.client.release()
+	})
+	return err
+}
+
+// Subscriber читает поток durable consumer'ом. Реализует bus.Subscriber.
+type Subscriber struct {
+	client  *Client
+	js      jetstream.JetStream
+	stream  string
+	durable string
+	svc     bus.OrderHandler
+	log     *zap.Logger
+
+	mu     sync.Mutex
+	closed bool
+	// it и done заданы, пока выполняется Run; done закрывается при выходе из Run
+	it        jetstream.MessagesContext
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Subscriber создаёт подписчика потока stream. durable — имя durable consumer'а, общее для
+// всех экземпляров приложения (аналог группы Kafka): сообщения распределяются между ними.
+func (c *Client) Subscriber(stream, durable string, svc bus.OrderHandler) *Subscriber {
+	c.acquire()
+	return &Subscriber{client: c, js: c.js, stream: stream, durable: durable, svc: svc, log: c.log}
+}
+
+func (s *Subscriber) Run(ctx context.Context) error {
+	cons, err := s.js.CreateOrUpdateConsumer(ctx, s.stream, jetstream.ConsumerConfig{
+		Durable:   s.durable,
+		AckPolicy: jetstream.AckExplicitPolicy,
+		AckWait:   30 * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create consumer %s: %w", s.durable, err)
+	}
+	it, err := cons.Messages()
+	if err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		it.Stop()
+		return nil
+	}
+	s.it, s.done = it, done
+	s.mu.Unlock()
+	stop := context.AfterFunc(ctx, it.Stop)
+	defer stop()
+	defer it.Stop()
+
+	for {
+		msg, err := it.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				s.log.Info("context done, stopping nats subscriber")
+				return ctx.Err()
+			}
+			if errors.Is(err, jetstream.ErrMsgIteratorClosed) {
+				s.log.Info("nats subscriber closed")
+				return nil
+			}
+			return err
+		}
+		s.handle(ctx, msg)
+	}
+}
+
+// handle передаёт сообщение обработчику и подтверждает его. Как и consumer Kafka без DLQ,
+// сообщение подтверждается и при ошибке обработки, чтобы оно не доставлялось повторно.
+func (s *Subscriber) handle(ctx context.Context, msg jetstream.Msg) {
+	env := envelope(msg)
+	msgCtx := bus.MessageContext(ctx, env)
+	log := logger.FromContext(msgCtx, s.log)
+	if err := s.svc.HandleOrder(msgCtx, env); err != nil {
+		log.Error("failed to handle message", zap.Error(err))
+	}
+	if err := msg.Ack(); err != nil {
+		log.Error("failed to ack message", zap.Error(err))
+	}
+}
+
+// Close прекращает получение новых сообщений, дожидается обработки уже полученных
+// и освобождает подключение.
+func (s *Subscriber) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		it, done := s.it, s.done
+		s.mu.Unlock()
+		if it != nil {
+			it.Drain()
+			<-done
+		}
+		err = s.client.release()
+	})
+	return err
+}
+
+// envelope переводит сообщение NATS в domain.Envelope. Ключ передаётся в заголовке keyHeader
+// и в Envelope.Headers не попадает.
+func envelope(msg jetstream.Msg) domain.Envelope {
+	env := domain.Envelope{Key: msg.Headers().Get(keyHeader), Payload: msg.Data()}
+	for name, values := range msg.Headers() {
+		if name == keyHeader || len(values) == 0 {
+			continue
+		}
+		if env.Headers == nil {
+			env.Headers = make(map[string]string)
+		}
+		env.Headers[name] = values[0]
+	}
+	return env
+}
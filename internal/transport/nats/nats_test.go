@@ -0,0 +1,129 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/requestid"
+	"wb-l0-go/internal/transport/bus"
+)
+
+// fakeMsg — сообщение JetStream поверх nats.Msg, запоминающее подтверждения.
+// Методы, не используемые подписчиком, не реализованы.
+type fakeMsg struct {
+	jetstream.Msg
+	msg    *nats.Msg
+	acks   int
+	naks   int
+	ackErr error
+}
+
+func (m *fakeMsg) Data() []byte { return m.msg.Data }
+
+func (m *fakeMsg) Headers() nats.Header { return m.msg.Header }
+
+func (m *fakeMsg) Ack() error {
+	m.acks++
+	return m.ackErr
+}
+
+func (m *fakeMsg) Nak() error {
+	m.naks++
+	return nil
+}
+
+func TestPublisherMessage(t *testing.T) {
+	p := &Publisher{subject: "orders"}
+
+	msg := p.message(bus.Message{
+		Key:   "order-1",
+		Value: []byte(`{"order_uid":"order-1"}`),
+		Headers: map[string]string{
+			domain.HeaderContentType:   "application/json",
+			domain.HeaderCorrelationID: "corr-1",
+		},
+	})
+	assert.Equal(t, "orders", msg.Subject)
+	assert.Equal(t, []byte(`{"order_uid":"order-1"}`), msg.Data)
+	assert.Equal(t, "order-1", msg.Header.Get(keyHeader))
+	// Имена заголовков передаются без изменения регистра
+	assert.Equal(t, "application/json", msg.Header.Get(domain.HeaderContentType))
+	assert.Equal(t, "corr-1", msg.Header.Get(domain.HeaderCorrelationID))
+
+	msg = p.message(bus.Message{Value: []byte(`{}`)})
+	assert.Empty(t, msg.Header, "empty key must not produce a header")
+}
+
+func TestEnvelope(t *testing.T) {
+	p := &Publisher{subject: "orders"}
+	headers := map[string]string{
+		domain.HeaderContentType:   "application/msgpack",
+		domain.HeaderCorrelationID: "corr-1",
+		domain.HeaderSourceSystem:  "crm",
+	}
+	msg := p.message(bus.Message{Key: "order-1", Value: []byte("payload"), Headers: headers})
+
+	env := envelope(&fakeMsg{msg: msg})
+	assert.Equal(t, domain.Envelope{Key: "order-1", Payload: []byte("payload"), Headers: headers}, env)
+
+	t.Run("without headers", func(t *testing.T) {
+		env := envelope(&fakeMsg{msg: &nats.Msg{Data: []byte("payload")}})
+		assert.Equal(t, domain.Envelope{Payload: []byte("payload")}, env)
+	})
+
+	t.Run("first value of repeated header", func(t *testing.T) {
+		msg := nats.NewMsg("orders")
+		msg.Header.Add(domain.HeaderSourceSystem, "crm")
+		msg.Header.Add(domain.HeaderSourceSystem, "erp")
+		env := envelope(&fakeMsg{msg: msg})
+		assert.Equal(t, map[string]string{domain.HeaderSourceSystem: "crm"}, env.Headers)
+	})
+}
+
+func TestSubscriberHandle(t *testing.T) {
+	errInvalid := errors.New("order validation failed")
+	var got []domain.Envelope
+	var requestIDs []string
+	handler := bus.HandlerFunc(func(ctx context.Context, env domain.Envelope) error {
+		got = append(got, env)
+		requestIDs = append(requestIDs, requestid.FromContext(ctx))
+		if env.Key == "bad" {
+			return errInvalid
+		}
+		return nil
+	})
+	s := &Subscriber{svc: handler, log: zap.NewNop()}
+	p := &Publisher{subject: "orders"}
+	ctx := context.Background()
+
+	good := &fakeMsg{msg: p.message(bus.Message{
+		Key: "good", Value: []byte("{}"), Headers: map[string]string{domain.HeaderRequestID: "req-1"},
+	})}
+	s.handle(ctx, good)
+	assert.Equal(t, 1, good.acks)
+	assert.Zero(t, good.naks)
+
+	// Как и consumer Kafka, подписчик подтверждает сообщение и при ошибке обработки,
+	// иначе JetStream доставлял бы его повторно после AckWait
+	bad := &fakeMsg{msg: p.message(bus.Message{Key: "bad", Value: []byte("{")})}
+	s.handle(ctx, bad)
+	assert.Equal(t, 1, bad.acks)
+	assert.Zero(t, bad.naks)
+
+	// Ошибка подтверждения только логируется
+	failedAck := &fakeMsg{msg: p.message(bus.Message{Key: "good", Value: []byte("{}")}), ackErr: nats.ErrConnectionClosed}
+	s.handle(ctx, failedAck)
+	assert.Equal(t, 1, failedAck.acks)
+
+	require.Len(t, got, 3)
+	assert.Equal(t, []string{"good", "bad", "good"}, []string{got[0].Key, got[1].Key, got[2].Key})
+	assert.Equal(t, "req-1", requestIDs[0], "handler context must carry the request id from headers")
+}
@@ -0,0 +1,112 @@
+// Package problem формирует ответы с ошибками в формате RFC 7807 (application/problem+json).
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+
+	gin "github.com/gin-gonic/gin"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/requestid"
+)
+
+// ContentType — тип содержимого ответа с ошибкой
+const ContentType = "application/problem+json"
+
+// Problem — описание ошибки по RFC 7807.
+type Problem struct {
+	// Type — URI типа ошибки, about:blank означает, что смысл ошибки определяется кодом ответа
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance — путь запроса, при обработке которого произошла ошибка
+	Instance string `json:"instance,omitempty"`
+	// RequestID — идентификатор запроса из заголовка X-Request-ID
+	RequestID string `json:"request_id,omitempty"`
+	// Extensions — дополнительные поля ответа (RFC 7807, раздел 3.2)
+	Extensions map[string]any `json:"-" swaggerignore:"true"`
+}
+
+// New создаёт описание ошибки запроса r.
+func New(r *http.Request, status int, detail string) Problem {
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: requestid.FromContext(r.Context()),
+	}
+}
+
+// reserved — поля Problem, которые нельзя задать через With
+var reserved = map[string]bool{
+	"type": true, "title": true, "status": true, "detail": true, "instance": true, "request_id": true,
+}
+
+// With возвращает копию описания с дополнительным полем. Имя стандартного поля (type, title, status,
+// detail, instance, request_id) — ошибка программиста, поэтому With паникует, а не теряет значение.
+func (p Problem) With(key string, value any) Problem {
+	if reserved[key] {
+		panic("problem: extension " + key + " collides with a standard member")
+	}
+	ext := maps.Clone(p.Extensions)
+	if ext == nil {
+		ext = map[string]any{}
+	}
+	ext[key] = value
+	p.Extensions = ext
+	return p
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type base Problem
+	raw, err := json.Marshal(base(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return raw, err
+	}
+	fields := make(map[string]any, len(p.Extensions)+7)
+	for k, v := range p.Extensions {
+		fields[k] = v
+	}
+	var std map[string]any
+	if err := json.Unmarshal(raw, &std); err != nil {
+		return nil, err
+	}
+	// With не допускает расширений с именами стандартных полей, поэтому они не пересекаются
+	maps.Copy(fields, std)
+	return json.Marshal(fields)
+}
+
+// Write отправляет описание ошибки и прерывает обработку запроса.
+func Write(c *gin.Context, p Problem) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		body, _ = json.Marshal(New(c.Request, http.StatusInternalServerError, "failed to encode error"))
+		p.Status = http.StatusInternalServerError
+	}
+	c.Abort()
+	c.Data(p.Status, ContentType, body)
+}
+
+// Error отправляет ошибку с кодом status и описанием detail.
+func Error(c *gin.Context, status int, detail string) {
+	Write(c, New(c.Request, status, detail))
+}
+
+// Status возвращает код ответа для ошибки репозитория или сервиса.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidArgument):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
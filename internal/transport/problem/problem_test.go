@@ -0,0 +1,57 @@
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gin "github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"wb-l0-go/internal/domain"
+	"wb-l0-go/internal/requestid"
+)
+
+func TestWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodGet, "/orders/x?limit=1", nil)
+	c.Request = req.WithContext(requestid.WithContext(req.Context(), "req-1"))
+
+	Write(c, New(c.Request, http.StatusNotFound, "order not found").With("order_uid", "x").With("publish_status", "published"))
+
+	assert.True(t, c.IsAborted())
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]any{
+		"type":           "about:blank",
+		"title":          "Not Found",
+		"status":         float64(http.StatusNotFound),
+		"detail":         "order not found",
+		"instance":       "/orders/x",
+		"request_id":     "req-1",
+		"order_uid":      "x",
+		"publish_status": "published",
+	}, body)
+}
+
+func TestWith_RejectsStandardMembers(t *testing.T) {
+	p := New(httptest.NewRequest(http.MethodGet, "/", nil), http.StatusBadRequest, "")
+	for _, key := range []string{"type", "title", "status", "detail", "instance", "request_id"} {
+		assert.Panics(t, func() { p.With(key, "x") }, key)
+	}
+	assert.NotPanics(t, func() { p.With("state", "draining") })
+}
+
+func TestStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, Status(fmt.Errorf("order x: %w", domain.ErrNotFound)))
+	assert.Equal(t, http.StatusBadRequest, Status(fmt.Errorf("%w: limit", domain.ErrInvalidArgument)))
+	assert.Equal(t, http.StatusConflict, Status(domain.ErrConflict))
+	assert.Equal(t, http.StatusInternalServerError, Status(fmt.Errorf("boom")))
+}
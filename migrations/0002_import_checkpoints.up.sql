@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS import_checkpoints (
+    import_id TEXT PRIMARY KEY,
+    line INTEGER NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
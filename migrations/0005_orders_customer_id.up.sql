@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON orders ((payload->>'customer_id'), created_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_items;
+DROP INDEX IF EXISTS idx_orders_track_number;
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_orders_track_number ON orders ((payload->>'track_number'));
+-- jsonb_path_ops поддерживает только @>, но индекс меньше и быстрее стандартного jsonb_ops
+CREATE INDEX IF NOT EXISTS idx_orders_items ON orders USING GIN ((payload->'items') jsonb_path_ops);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_orders_search_vector;
+DROP TRIGGER IF EXISTS orders_search_vector_update ON orders;
+DROP FUNCTION IF EXISTS orders_search_vector_update();
+ALTER TABLE orders DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS orders_search_vector(JSONB);
+DROP FUNCTION IF EXISTS orders_search_document(JSONB);
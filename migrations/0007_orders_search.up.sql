@@ -0,0 +1,39 @@
+-- Текст заказа для полнотекстового поиска: имя получателя, город, адрес, названия и бренды товаров
+CREATE OR REPLACE FUNCTION orders_search_document(payload JSONB) RETURNS TEXT
+LANGUAGE SQL IMMUTABLE AS $$
+    SELECT concat_ws(' ',
+        payload->'delivery'->>'name',
+        payload->'delivery'->>'city',
+        payload->'delivery'->>'address',
+        (SELECT string_agg(concat_ws(' ', item->>'name', item->>'brand'), ' ')
+           FROM jsonb_array_elements(CASE WHEN jsonb_typeof(payload->'items') = 'array' THEN payload->'items' ELSE '[]' END) AS item))
+$$;
+
+-- Вектор поиска: совпадения в имени получателя и товарах ранжируются выше, чем в адресе.
+-- Конфигурация simple не зависит от языка: данные содержат и русский, и английский текст.
+CREATE OR REPLACE FUNCTION orders_search_vector(payload JSONB) RETURNS TSVECTOR
+LANGUAGE SQL IMMUTABLE AS $$
+    SELECT setweight(to_tsvector('simple', coalesce(payload->'delivery'->>'name', '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(
+            (SELECT string_agg(concat_ws(' ', item->>'name', item->>'brand'), ' ')
+               FROM jsonb_array_elements(CASE WHEN jsonb_typeof(payload->'items') = 'array' THEN payload->'items' ELSE '[]' END) AS item), '')), 'B') ||
+        setweight(to_tsvector('simple', concat_ws(' ', payload->'delivery'->>'city', payload->'delivery'->>'address')), 'C')
+$$;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+UPDATE orders SET search_vector = orders_search_vector(payload);
+
+CREATE OR REPLACE FUNCTION orders_search_vector_update() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.search_vector := orders_search_vector(NEW.payload);
+    RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS orders_search_vector_update ON orders;
+CREATE TRIGGER orders_search_vector_update
+    BEFORE INSERT OR UPDATE OF payload ON orders
+    FOR EACH ROW EXECUTE FUNCTION orders_search_vector_update();
+
+CREATE INDEX IF NOT EXISTS idx_orders_search_vector ON orders USING GIN (search_vector);